@@ -0,0 +1,78 @@
+/*
+MIT License
+
+# Copyright (c) 2022 Drumato
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package cli
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/Drumato/promqlinter/pkg/linter"
+)
+
+// baselineOptions returns the linter options for --baseline and --write-baseline.
+// the returned recorder is non-nil if --write-baseline is given.
+func baselineOptions() ([]linter.PromQLinterOption, *linter.Baseline, error) {
+	if GlobalWriteBaselineRO != "" {
+		recorder := linter.NewBaseline()
+		return []linter.PromQLinterOption{linter.WithBaselineRecorder(recorder)}, recorder, nil
+	}
+
+	if GlobalBaselineRO == "" {
+		return nil, nil, nil
+	}
+
+	f, err := os.Open(GlobalBaselineRO)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer f.Close()
+
+	b, err := linter.ReadBaseline(f)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%s: %w", GlobalBaselineRO, err)
+	}
+
+	return []linter.PromQLinterOption{linter.WithBaseline(b)}, nil, nil
+}
+
+// writeBaseline writes the recorded findings to the --write-baseline file.
+func writeBaseline(recorder *linter.Baseline) error {
+	f, err := os.Create(GlobalWriteBaselineRO)
+	if err != nil {
+		return err
+	}
+
+	if err := recorder.Write(f); err != nil {
+		f.Close()
+		return err
+	}
+
+	if err := f.Close(); err != nil {
+		return err
+	}
+
+	fmt.Printf("the baseline is written to %s\n", GlobalWriteBaselineRO)
+	return nil
+}
@@ -57,5 +57,7 @@ func NewCLI() *cobra.Command {
 	}
 
 	defineCLIFlags(c)
+	c.AddCommand(newGraphCommand())
+	c.AddCommand(newExplainCommand())
 	return c
 }
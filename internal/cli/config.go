@@ -0,0 +1,73 @@
+/*
+MIT License
+
+# Copyright (c) 2022 Drumato
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package cli
+
+import (
+	"os"
+
+	"github.com/Drumato/promqlinter/pkg/linter"
+	"github.com/Drumato/promqlinter/pkg/linter/plugin"
+	"sigs.k8s.io/yaml"
+)
+
+// cliConfig is the configuration file of the CLI.
+type cliConfig struct {
+	plugin.Config
+
+	// Severities overrides the levels of the diagnostics.
+	Severities []linter.SeverityOverride `json:"severities,omitempty"`
+}
+
+// loadConfig reads the configuration file.
+// the default configuration is used for the fields that the file doesn't specify.
+func loadConfig(configPath string) (*cliConfig, error) {
+	config := &cliConfig{Config: *plugin.DefaultConfig()}
+	if configPath != "" {
+		out, err := os.ReadFile(configPath)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := yaml.UnmarshalStrict(out, config); err != nil {
+			return nil, err
+		}
+	}
+
+	for i := range config.Severities {
+		if err := config.Severities[i].Validate(); err != nil {
+			return nil, err
+		}
+	}
+
+	config.AddDeniedLabels(GlobalDeniedLabelsRO)
+	if GlobalPrometheusVersionRO != "" {
+		config.PrometheusVersion = GlobalPrometheusVersionRO
+	}
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+
+	return config, nil
+}
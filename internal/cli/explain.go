@@ -0,0 +1,81 @@
+/*
+MIT License
+
+# Copyright (c) 2022 Drumato
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package cli
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/Drumato/promqlinter/pkg/codes"
+	"github.com/spf13/cobra"
+)
+
+const (
+	explainExample = `
+	# print the description of the check with the examples
+	promqlinter explain PQL0201
+
+	# list all the checks
+	promqlinter explain
+	`
+)
+
+// newExplainCommand initializes the explain subcommand.
+func newExplainCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:     "explain [code]",
+		Short:   "Print the description of the check with the code",
+		Example: explainExample,
+		Args:    cobra.MaximumNArgs(1),
+		RunE:    explain,
+	}
+}
+
+func explain(cmd *cobra.Command, args []string) error {
+	if len(args) == 0 {
+		return listChecks()
+	}
+
+	doc, err := codes.Explain(args[0])
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprint(os.Stdout, doc)
+	return err
+}
+
+// listChecks prints the codes, the plugins and the titles of all the checks.
+func listChecks() error {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	for _, c := range codes.All() {
+		if _, err := fmt.Fprintf(w, "%s\t%s\t%s\n", c.Code, c.Plugin, c.Title); err != nil {
+			return err
+		}
+	}
+
+	return w.Flush()
+}
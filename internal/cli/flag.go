@@ -24,17 +24,35 @@ SOFTWARE.
 
 package cli
 
-import "github.com/spf13/cobra"
+import (
+	"github.com/Drumato/promqlinter/pkg/codes"
+	"github.com/spf13/cobra"
+)
 
 var (
+	GlobalConfigPathRO            string
 	GlobalK8sManifestRO           string
 	GlobalRecursiveRO             bool
 	GlobalDiagnosticLevelFilterRO string
 	GlobalDeniedLabelsRO          string
 	GlobalUseAnsiColorStringRO    string
+	GlobalPrometheusVersionRO     string
+	GlobalGraphFormatRO           string
+	GlobalReportUnusedRO          bool
+	GlobalDashboardsRO            string
+	GlobalBaselineRO              string
+	GlobalWriteBaselineRO         string
+	GlobalDocURLTemplateRO        string
 )
 
 func defineCLIFlags(c *cobra.Command) {
+	c.Flags().StringVar(
+		&GlobalConfigPathRO,
+		"config",
+		"",
+		"the configuration file of the linter plugins",
+	)
+
 	c.Flags().StringVarP(
 		&GlobalDeniedLabelsRO,
 		"denied-labels",
@@ -51,7 +69,7 @@ func defineCLIFlags(c *cobra.Command) {
 		"the diagnostic level filter(info/warning/error)",
 	)
 
-	c.Flags().StringVarP(
+	c.PersistentFlags().StringVarP(
 		&GlobalK8sManifestRO,
 		"input-k8s-manifest",
 		"i",
@@ -59,7 +77,7 @@ func defineCLIFlags(c *cobra.Command) {
 		"the target PrometheusRule resource",
 	)
 
-	c.Flags().BoolVarP(
+	c.PersistentFlags().BoolVarP(
 		&GlobalRecursiveRO,
 		"recursive",
 		"r",
@@ -75,4 +93,54 @@ func defineCLIFlags(c *cobra.Command) {
 		"determine whether the promqlinter's reports are colored with ANSI codes",
 	)
 
+	c.Flags().StringVar(
+		&GlobalPrometheusVersionRO,
+		"prometheus-version",
+		"",
+		"the target Prometheus version that the expressions must be compatible with",
+	)
+
+	c.Flags().BoolVar(
+		&GlobalReportUnusedRO,
+		"report-unused",
+		false,
+		"determine whether the recording rules that are never read are reported",
+	)
+
+	c.Flags().StringVar(
+		&GlobalDashboardsRO,
+		"dashboards",
+		"",
+		"the comma-separated Grafana dashboard JSON files/directories that --report-unused considers",
+	)
+
+	c.Flags().StringVar(
+		&GlobalBaselineRO,
+		"baseline",
+		"",
+		"the baseline file whose findings are not reported",
+	)
+
+	c.Flags().StringVar(
+		&GlobalWriteBaselineRO,
+		"write-baseline",
+		"",
+		"write the current findings to the baseline file instead of reporting them",
+	)
+
+	c.Flags().StringVar(
+		&GlobalDocURLTemplateRO,
+		"doc-url-template",
+		codes.DefaultDocURLTemplate,
+		"the template of the document URL of the checks ({code} is replaced with the code; empty disables the URLs)",
+	)
+}
+
+func defineGraphFlags(c *cobra.Command) {
+	c.Flags().StringVar(
+		&GlobalGraphFormatRO,
+		"format",
+		"dot",
+		"the output format of the graph(dot/json)",
+	)
 }
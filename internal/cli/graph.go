@@ -0,0 +1,78 @@
+/*
+MIT License
+
+# Copyright (c) 2022 Drumato
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package cli
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/Drumato/promqlinter/pkg/rulegraph"
+	"github.com/spf13/cobra"
+)
+
+const (
+	graphExample = `
+	# print the rule dependency graph of the PrometheusRule manifests in DOT
+	promqlinter graph -r -i ./examples/manifests/ | dot -Tsvg > rules.svg
+
+	# print the rule dependency graph in JSON
+	promqlinter graph -r -i ./examples/manifests/ --format json
+	`
+)
+
+// newGraphCommand initializes the graph subcommand.
+func newGraphCommand() *cobra.Command {
+	c := &cobra.Command{
+		Use:     "graph",
+		Short:   "Print the dependency graph of the rules in DOT/JSON",
+		Example: graphExample,
+		Args:    cobra.NoArgs,
+		RunE:    graph,
+	}
+
+	defineGraphFlags(c)
+	return c
+}
+
+func graph(cmd *cobra.Command, args []string) error {
+	if GlobalK8sManifestRO == "" {
+		return fmt.Errorf("--input-k8s-manifest must be specified")
+	}
+
+	rules, err := loadRules()
+	if err != nil {
+		return err
+	}
+
+	e := rulegraph.New(rules).Export()
+	switch GlobalGraphFormatRO {
+	case "dot":
+		return e.WriteDOT(os.Stdout)
+	case "json":
+		return e.WriteJSON(os.Stdout)
+	default:
+		return fmt.Errorf("--format must be one of dot/json")
+	}
+}
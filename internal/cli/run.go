@@ -27,17 +27,18 @@ package cli
 import (
 	"bufio"
 	"fmt"
-	"io"
+	"io/fs"
 	"os"
 	"path"
+	"path/filepath"
 	"strconv"
 	"strings"
 
 	"github.com/Drumato/promqlinter/pkg/linter"
 	"github.com/Drumato/promqlinter/pkg/linter/plugin"
-	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	"github.com/Drumato/promqlinter/pkg/manifest"
+	"github.com/Drumato/promqlinter/pkg/rulegraph"
 	"github.com/spf13/cobra"
-	"sigs.k8s.io/yaml"
 )
 
 var (
@@ -55,20 +56,39 @@ func run(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
+	config, err := loadConfig(GlobalConfigPathRO)
+	if err != nil {
+		return err
+	}
+
 	if len(GlobalK8sManifestRO) == 0 {
-		return runExprFromStdinMode(cmd, args, filter)
+		return runExprFromStdinMode(cmd, args, filter, config)
 	}
 
-	return runK8sManifestsMode(cmd, args, filter)
+	return runK8sManifestsMode(cmd, args, filter, config)
 }
 
 // runExprFromStdinMode runs the linter process with the given input from stdin.
-func runExprFromStdinMode(cmd *cobra.Command, args []string, filter linter.DiagnosticLevel) error {
-	l := linter.New(
-		linter.WithPlugins(plugin.Defaults(GlobalDeniedLabelsRO, promqlinterColorMode)...),
+func runExprFromStdinMode(
+	cmd *cobra.Command,
+	args []string,
+	filter linter.DiagnosticLevel,
+	config *cliConfig,
+) error {
+	options, recorder, err := baselineOptions()
+	if err != nil {
+		return err
+	}
+
+	options = append(
+		options,
+		linter.WithPlugins(plugin.DefaultsWithConfig(&config.Config, promqlinterColorMode)...),
+		linter.WithSeverityOverrides(config.Severities...),
+		linter.WithDocURLTemplate(GlobalDocURLTemplateRO),
 		linter.WithOutStream(os.Stdout),
 		linter.WithANSIColorMode(promqlinterColorMode),
 	)
+	l := linter.New(options...)
 
 	scanner := bufio.NewScanner(os.Stdin)
 
@@ -82,12 +102,8 @@ func runExprFromStdinMode(cmd *cobra.Command, args []string, filter linter.Diagn
 	if err != nil {
 		return err
 	}
-	if result.Failed() {
-		return fmt.Errorf("some of linter plugins detects the filtered rules")
-	}
 
-	fmt.Println("ok")
-	return nil
+	return finish(result.Failed(), recorder)
 }
 
 // runK8sManifestsMode runs the lint process with the k8s manifests.
@@ -95,56 +111,136 @@ func runK8sManifestsMode(
 	cmd *cobra.Command,
 	args []string,
 	filter linter.DiagnosticLevel,
+	config *cliConfig,
 ) error {
-	l := linter.New(
+	// all rules are collected first for the cross-file checks.
+	rules, err := loadRules()
+	if err != nil {
+		return err
+	}
+
+	options, recorder, err := baselineOptions()
+	if err != nil {
+		return err
+	}
+
+	graph := rulegraph.New(rules)
+	options = append(
+		options,
 		linter.WithOutStream(os.Stdout),
-		linter.WithPlugins(plugin.Defaults(GlobalDeniedLabelsRO, promqlinterColorMode)...),
+		linter.WithANSIColorMode(promqlinterColorMode),
+		linter.WithPlugins(plugin.DefaultsWithConfig(&config.Config, promqlinterColorMode)...),
+		linter.WithSeverityOverrides(config.Severities...),
+		linter.WithDocURLTemplate(GlobalDocURLTemplateRO),
+		linter.WithPlugin(plugin.NewDuplicateRulePlugin(graph, promqlinterColorMode)),
+		linter.WithPlugin(plugin.NewRuleDependencyPlugin(graph, promqlinterColorMode)),
 	)
 
+	if GlobalReportUnusedRO {
+		dashboardIdents, err := loadDashboards(GlobalDashboardsRO)
+		if err != nil {
+			return err
+		}
+
+		p := plugin.NewUnusedRecordingRulePlugin(graph, dashboardIdents, promqlinterColorMode)
+		options = append(options, linter.WithPlugin(p))
+	}
+
+	l := linter.New(options...)
+
+	// all rules are linted before failing so that every finding is reported at once.
+	failed := false
+	for _, rule := range rules {
+		result, err := l.ExecuteWithContext(rule.Expr.StrVal, rule.Context(), filter)
+		if err != nil {
+			return err
+		}
+		if result.Failed() {
+			failed = true
+		}
+	}
+
+	return finish(failed, recorder)
+}
+
+// finish writes the baseline if it's recorded and reports the result of the lint process.
+// the baseline is written even if the lint process fails
+// since the findings like the parse errors are reported while recording the baseline.
+func finish(failed bool, recorder *linter.Baseline) error {
+	if recorder != nil {
+		if err := writeBaseline(recorder); err != nil {
+			return err
+		}
+	}
+
+	if failed {
+		return fmt.Errorf("some of linter plugins detects the filtered rules")
+	}
+
+	if recorder == nil {
+		fmt.Println("ok")
+	}
+
+	return nil
+}
+
+// loadRules reads all the rules in the target manifests.
+func loadRules() ([]*manifest.Rule, error) {
 	var manifests []string
 	if !GlobalRecursiveRO {
 		manifests = []string{GlobalK8sManifestRO}
 	} else {
 		var err error
 		if manifests, err = searchAllTargetManifests(GlobalK8sManifestRO); err != nil {
-			return err
+			return nil, err
 		}
 	}
 
+	rules := []*manifest.Rule{}
 	for _, manifestPath := range manifests {
-		f, err := os.Open(manifestPath)
+		f, err := manifest.Load(manifestPath)
 		if err != nil {
-			return err
-		}
-		out, err := io.ReadAll(f)
-		if err != nil {
-			return err
+			return nil, err
 		}
 
-		if err := f.Close(); err != nil {
-			return err
-		}
+		rules = append(rules, f.Rules...)
+	}
 
-		ruleManifest := monitoringv1.PrometheusRule{}
-		if err := yaml.Unmarshal(out, &ruleManifest); err != nil {
-			return err
-		}
+	return rules, nil
+}
 
-		for _, rg := range ruleManifest.Spec.Groups {
-			for _, rule := range rg.Rules {
-				result, err := l.Execute(rule.Expr.StrVal, filter)
-				if err != nil {
-					return err
-				}
-				if result.Failed() {
-					return fmt.Errorf("some of linter plugins detects the filtered rules")
-				}
+// loadDashboards reads the identifiers in the queries of the given Grafana dashboards.
+// the directories are searched recursively for the JSON files.
+// it returns nil if no dashboards are given.
+func loadDashboards(dashboardsFlagValue string) ([]string, error) {
+	if dashboardsFlagValue == "" {
+		return nil, nil
+	}
+
+	idents := []string{}
+	for _, p := range strings.Split(dashboardsFlagValue, ",") {
+		err := filepath.WalkDir(strings.TrimSpace(p), func(entryPath string, entry fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if entry.IsDir() || filepath.Ext(entryPath) != ".json" {
+				return nil
+			}
+
+			dashboardIdents, err := rulegraph.LoadDashboard(entryPath)
+			if err != nil {
+				return err
 			}
+
+			idents = append(idents, dashboardIdents...)
+			return nil
+		})
+		if err != nil {
+			return nil, err
 		}
 	}
 
-	fmt.Println("ok")
-	return nil
+	return idents, nil
 }
 
 // searchAlTargetManifests searches the k8s manifests recursively.
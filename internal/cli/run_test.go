@@ -0,0 +1,108 @@
+/*
+MIT License
+
+# Copyright (c) 2022 Drumato
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package cli
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const unusedSuppressionManifest = `apiVersion: monitoring.coreos.com/v1
+kind: PrometheusRule
+metadata:
+  name: example
+spec:
+  groups:
+  - name: example
+    rules:
+    # promqlinter:ignore label-matchers nothing to silence
+    - record: job:up:sum
+      expr: sum by (job) (up)
+`
+
+// runCLI runs the CLI with the given arguments and returns the output to stdout.
+func runCLI(t *testing.T, args ...string) (string, error) {
+	r, w, err := os.Pipe()
+	assert.NoError(t, err)
+
+	stdout := os.Stdout
+	os.Stdout = w
+	defer func() { os.Stdout = stdout }()
+
+	c := NewCLI()
+	c.SetArgs(args)
+	runErr := c.Execute()
+	assert.NoError(t, w.Close())
+
+	out, err := io.ReadAll(r)
+	assert.NoError(t, err)
+
+	return string(out), runErr
+}
+
+func TestRunK8sManifestsModeWithoutColor(t *testing.T) {
+	manifestPath := filepath.Join(t.TempDir(), "rules.yaml")
+	assert.NoError(t, os.WriteFile(manifestPath, []byte(unusedSuppressionManifest), 0o644))
+
+	out, err := runCLI(t, "-i", manifestPath, "-c", "false", "-f", "warning")
+	assert.Error(t, err)
+
+	assert.Contains(t, out, "suppressions<[WARN] PQL0003 (1:1) the suppression `# promqlinter:ignore label-matchers nothing to silence` silences nothing")
+	assert.NotContains(t, out, "\x1b[")
+}
+
+const parseErrorManifest = `apiVersion: monitoring.coreos.com/v1
+kind: PrometheusRule
+metadata:
+  name: example
+spec:
+  groups:
+  - name: example
+    rules:
+    - record: job:up:sum
+      expr: sum by (job, job) (up)
+    - alert: Broken
+      expr: up{
+`
+
+func TestRunK8sManifestsModeWriteBaselineOnFailure(t *testing.T) {
+	dir := t.TempDir()
+	manifestPath := filepath.Join(dir, "rules.yaml")
+	baselinePath := filepath.Join(dir, "baseline.json")
+	assert.NoError(t, os.WriteFile(manifestPath, []byte(parseErrorManifest), 0o644))
+
+	out, err := runCLI(t, "-i", manifestPath, "-c", "false", "-f", "warning", "--write-baseline", baselinePath)
+	assert.Error(t, err)
+	assert.Contains(t, out, "promql/parser<")
+	assert.Contains(t, out, "the baseline is written to "+baselinePath)
+
+	baseline, err := os.ReadFile(baselinePath)
+	assert.NoError(t, err)
+	assert.Contains(t, string(baseline), "aggregation-grouping")
+}
@@ -0,0 +1,267 @@
+/*
+MIT License
+
+# Copyright (c) 2022 Drumato
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+// Package codes defines the stable codes of the checks and their documents.
+package codes
+
+import (
+	"embed"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// DefaultDocURLTemplate is the default template of the document URL of a check.
+// `{code}` is replaced with the code of the check.
+const DefaultDocURLTemplate = "https://github.com/Drumato/promqlinter/blob/main/pkg/codes/docs/{code}.md"
+
+const (
+	ParseError           = "PQL0001"
+	MalformedSuppression = "PQL0002"
+	UnusedSuppression    = "PQL0003"
+
+	DeniedLabel = "PQL0101"
+
+	ContradictoryMatchers = "PQL0201"
+	RedundantMatcher      = "PQL0202"
+
+	RedundantRegexAnchors = "PQL0301"
+	CaseInsensitiveRegex  = "PQL0302"
+	LeadingWildcardRegex  = "PQL0303"
+	TrailingWildcardRegex = "PQL0304"
+	CaseOnlyAlternatives  = "PQL0305"
+	LiteralRegex          = "PQL0306"
+	LiteralSetRegex       = "PQL0307"
+
+	RangeTooLong            = "PQL0401"
+	RangeTooShort           = "PQL0402"
+	InstantRateRangeTooLong = "PQL0403"
+	SubqueryStepNotDivisor  = "PQL0404"
+
+	QueryCostExceeded = "PQL0501"
+
+	DeniedFunction   = "PQL0601"
+	DeniedAtModifier = "PQL0602"
+	DeniedOffset     = "PQL0603"
+
+	IncompatibleFeature = "PQL0701"
+
+	AlertNeverFires     = "PQL0801"
+	AlertAlwaysFires    = "PQL0802"
+	AlertBoolComparison = "PQL0803"
+	AlertWithoutFilter  = "PQL0804"
+
+	EmptyConstantExpr = "PQL0901"
+	ConstantExpr      = "PQL0902"
+	SelfComparison    = "PQL0903"
+
+	DivisionByZero = "PQL1001"
+
+	AbsentWithoutSelector = "PQL1101"
+	AbsentCarriedLabels   = "PQL1102"
+	AbsentDroppedLabels   = "PQL1103"
+	AbsentWithoutFor      = "PQL1104"
+
+	InvalidLabelReplaceRegex = "PQL1201"
+	InvalidLabelName         = "PQL1202"
+	MissingCaptureGroup      = "PQL1203"
+	MissingSourceLabel       = "PQL1204"
+
+	RankingInRule = "PQL1301"
+	SortInRule    = "PQL1302"
+
+	EmptyGrouping          = "PQL1401"
+	GroupingPolicy         = "PQL1402"
+	DuplicateGroupingLabel = "PQL1403"
+
+	HighCardinalityGrouping  = "PQL1501"
+	UnboundedCountValues     = "PQL1502"
+	HighCardinalityRecording = "PQL1503"
+
+	RedundantParens    = "PQL1601"
+	DoubleNegation     = "PQL1602"
+	NestedAggregation  = "PQL1603"
+	RateMultiplication = "PQL1604"
+	RangeFilter        = "PQL1605"
+
+	InvalidDuration               = "PQL1701"
+	RangeShorterThanInterval      = "PQL1702"
+	SubqueryStepMisaligned        = "PQL1703"
+	DurationNotMultipleOfInterval = "PQL1704"
+
+	DuplicateAlert         = "PQL1801"
+	DuplicateRecordingRule = "PQL1802"
+	DuplicateExpression    = "PQL1803"
+
+	RecordingRuleCycle      = "PQL1901"
+	UndefinedRecordedMetric = "PQL1902"
+	RecordedLater           = "PQL1903"
+
+	UnusedRecordingRule = "PQL2001"
+)
+
+// Check describes a check that reports the diagnostics with the code.
+type Check struct {
+	// Code is the stable code of the check.
+	Code string
+	// Plugin is the name of the plugin that runs the check.
+	Plugin string
+	// Title is the one-line summary of the check.
+	Title string
+}
+
+var checks = []Check{
+	{ParseError, "promql/parser", "the expression can't be parsed"},
+	{MalformedSuppression, "suppressions", "the suppression comment is malformed"},
+	{UnusedSuppression, "suppressions", "the suppression comment silences nothing"},
+
+	{DeniedLabel, "denied-labels", "the selector matches a denied label"},
+
+	{ContradictoryMatchers, "label-matchers", "the label matchers never match"},
+	{RedundantMatcher, "label-matchers", "the label matcher is redundant"},
+
+	{RedundantRegexAnchors, "regex-matchers", "the regex has redundant anchors"},
+	{CaseInsensitiveRegex, "regex-matchers", "the regex is case-insensitive"},
+	{LeadingWildcardRegex, "regex-matchers", "the regex starts with `.*`"},
+	{TrailingWildcardRegex, "regex-matchers", "the regex ends with `.*`"},
+	{CaseOnlyAlternatives, "regex-matchers", "the regex alternatives differ only by case"},
+	{LiteralRegex, "regex-matchers", "the regex is a literal"},
+	{LiteralSetRegex, "regex-matchers", "the regex is a set of literals"},
+
+	{RangeTooLong, "range-duration", "the range exceeds the maximum range"},
+	{RangeTooShort, "range-duration", "the range is too short for the scrape interval"},
+	{InstantRateRangeTooLong, "range-duration", "the range of `irate`/`idelta` is too long"},
+	{SubqueryStepNotDivisor, "range-duration", "the subquery step is not a divisor of the range"},
+
+	{QueryCostExceeded, "query-cost", "the query exceeds the cost budget"},
+
+	{DeniedFunction, "denied-functions", "the function is denied"},
+	{DeniedAtModifier, "denied-functions", "the `@` modifier is denied"},
+	{DeniedOffset, "denied-functions", "the `offset` modifier is denied"},
+
+	{IncompatibleFeature, "prometheus-compatibility", "the feature isn't supported by the target Prometheus"},
+
+	{AlertNeverFires, "alert-condition", "the alert never fires"},
+	{AlertAlwaysFires, "alert-condition", "the alert always fires"},
+	{AlertBoolComparison, "alert-condition", "the alert uses a `bool` comparison"},
+	{AlertWithoutFilter, "alert-condition", "the alert expression has no filter"},
+
+	{EmptyConstantExpr, "constant-expressions", "the expression always returns an empty vector"},
+	{ConstantExpr, "constant-expressions", "the expression is constant"},
+	{SelfComparison, "constant-expressions", "the scalar is compared with itself"},
+
+	{DivisionByZero, "division-by-zero", "the denominator may be zero"},
+
+	{AbsentWithoutSelector, "absent", "`absent` is applied to a non-selector"},
+	{AbsentCarriedLabels, "absent", "the labels that the result of `absent` carries"},
+	{AbsentDroppedLabels, "absent", "the result of `absent` drops the labels of the matchers"},
+	{AbsentWithoutFor, "absent", "the alert with `absent` has no `for`"},
+
+	{InvalidLabelReplaceRegex, "label-functions", "the regex of `label_replace` is invalid"},
+	{InvalidLabelName, "label-functions", "the label name is invalid"},
+	{MissingCaptureGroup, "label-functions", "the replacement refers to a missing capture group"},
+	{MissingSourceLabel, "label-functions", "the source label doesn't exist on the input"},
+
+	{RankingInRule, "ranking", "`topk`/`bottomk` is used in a rule"},
+	{SortInRule, "ranking", "`sort`/`sort_desc` is used in a rule"},
+
+	{EmptyGrouping, "aggregation-grouping", "the aggregation has an empty grouping"},
+	{GroupingPolicy, "aggregation-grouping", "the grouping modifier violates the policy"},
+	{DuplicateGroupingLabel, "aggregation-grouping", "the grouping has a duplicated label"},
+
+	{HighCardinalityGrouping, "aggregation-grouping", "the aggregation groups by a high-cardinality label"},
+	{UnboundedCountValues, "cardinality", "`count_values` is applied to unbounded values"},
+	{HighCardinalityRecording, "cardinality", "the recording rule keeps a high-cardinality label"},
+
+	{RedundantParens, "simplify", "the parentheses are redundant"},
+	{DoubleNegation, "simplify", "the double negation can be removed"},
+	{NestedAggregation, "simplify", "the nested aggregation can be merged"},
+	{RateMultiplication, "simplify", "`rate` times the range is `increase`"},
+	{RangeFilter, "simplify", "the range filter can be chained"},
+
+	{InvalidDuration, "evaluation-interval", "the duration of the rule/group is invalid"},
+	{RangeShorterThanInterval, "evaluation-interval", "the range is shorter than the evaluation interval"},
+	{SubqueryStepMisaligned, "evaluation-interval", "the subquery step is not aligned with the evaluation interval"},
+	{DurationNotMultipleOfInterval, "evaluation-interval", "`for`/`keep_firing_for` is not a multiple of the evaluation interval"},
+
+	{DuplicateAlert, "duplicate-rules", "the alert is defined twice"},
+	{DuplicateRecordingRule, "duplicate-rules", "the recording rules record the same series"},
+	{DuplicateExpression, "duplicate-rules", "the same expression is used by another rule"},
+
+	{RecordingRuleCycle, "rule-dependencies", "the recording rule depends on itself"},
+	{UndefinedRecordedMetric, "rule-dependencies", "the recorded metric isn't recorded by any rule"},
+	{RecordedLater, "rule-dependencies", "the recorded metric is recorded later in the group"},
+
+	{UnusedRecordingRule, "unused-recording-rules", "the recording rule is never read"},
+}
+
+//go:embed docs/*.md
+var docs embed.FS
+
+// All returns all the checks in the order of the codes.
+func All() []Check {
+	all := make([]Check, len(checks))
+	copy(all, checks)
+	sort.Slice(all, func(i, j int) bool { return all[i].Code < all[j].Code })
+
+	return all
+}
+
+// Lookup returns the check with the code.
+// the code is case-insensitive.
+func Lookup(code string) (Check, bool) {
+	code = strings.ToUpper(code)
+	for _, c := range checks {
+		if c.Code == code {
+			return c, true
+		}
+	}
+
+	return Check{}, false
+}
+
+// Explain returns the long description of the check with the code.
+func Explain(code string) (string, error) {
+	c, ok := Lookup(code)
+	if !ok {
+		return "", fmt.Errorf("unknown code `%s`", code)
+	}
+
+	b, err := docs.ReadFile(fmt.Sprintf("docs/%s.md", c.Code))
+	if err != nil {
+		return "", err
+	}
+
+	return string(b), nil
+}
+
+// DocURL expands the template with the code.
+// it returns an empty string if the template or the code is empty.
+func DocURL(template, code string) string {
+	if template == "" || code == "" {
+		return ""
+	}
+
+	return strings.ReplaceAll(template, "{code}", code)
+}
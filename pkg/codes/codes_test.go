@@ -0,0 +1,102 @@
+/*
+MIT License
+
+# Copyright (c) 2022 Drumato
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package codes_test
+
+import (
+	"io/fs"
+	"os"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/Drumato/promqlinter/pkg/codes"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestChecksHaveDocs(t *testing.T) {
+	codePattern := regexp.MustCompile(`^PQL[0-9]{4}$`)
+	seen := map[string]struct{}{}
+
+	for _, c := range codes.All() {
+		assert.Regexp(t, codePattern, c.Code)
+		assert.NotContains(t, seen, c.Code, "the code is duplicated")
+		seen[c.Code] = struct{}{}
+
+		doc, err := codes.Explain(c.Code)
+		assert.NoError(t, err, c.Code)
+		assert.True(t, strings.HasPrefix(doc, "# "+c.Code+": "+c.Title+"\n"), c.Code)
+		assert.Contains(t, doc, "plugin: `"+c.Plugin+"`", c.Code)
+		assert.Contains(t, doc, "## Bad", c.Code)
+		assert.Contains(t, doc, "## Good", c.Code)
+	}
+
+	files, err := fs.Glob(os.DirFS("docs"), "*.md")
+	assert.NoError(t, err)
+	for _, f := range files {
+		assert.Contains(t, seen, strings.TrimSuffix(f, ".md"), "the document has no check")
+	}
+}
+
+func TestExplain(t *testing.T) {
+	doc, err := codes.Explain("pql0201")
+	assert.NoError(t, err)
+	assert.Contains(t, doc, "# PQL0201")
+
+	_, err = codes.Explain("PQL9999")
+	assert.Error(t, err)
+}
+
+func TestDocURL(t *testing.T) {
+	tests := []struct {
+		template string
+		code     string
+		expected string
+	}{
+		{
+			template: codes.DefaultDocURLTemplate,
+			code:     codes.DeniedLabel,
+			expected: "https://github.com/Drumato/promqlinter/blob/main/pkg/codes/docs/PQL0101.md",
+		},
+		{
+			template: "https://example.com/rules#{code}",
+			code:     codes.DeniedLabel,
+			expected: "https://example.com/rules#PQL0101",
+		},
+		{
+			template: "",
+			code:     codes.DeniedLabel,
+			expected: "",
+		},
+		{
+			template: codes.DefaultDocURLTemplate,
+			code:     "",
+			expected: "",
+		},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.expected, codes.DocURL(tt.template, tt.code))
+	}
+}
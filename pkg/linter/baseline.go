@@ -0,0 +1,144 @@
+/*
+MIT License
+
+# Copyright (c) 2022 Drumato
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package linter
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"sort"
+
+	"github.com/prometheus/prometheus/promql/parser"
+)
+
+// Baseline is the set of the known findings.
+// the linter doesn't report the findings in the baseline
+// so the linter can be adopted on the repositories that have a lot of violations.
+type Baseline struct {
+	// Entries are the known findings.
+	Entries []BaselineEntry `json:"entries"`
+
+	// counts holds the number of the findings that aren't matched yet for each key.
+	counts map[BaselineEntry]int
+}
+
+// BaselineEntry is a group of the known findings.
+type BaselineEntry struct {
+	// File is the path of the manifest that defines the rule.
+	File string `json:"file,omitempty"`
+	// Rule is the alert name or the recorded metric name of the rule.
+	Rule string `json:"rule,omitempty"`
+	// Plugin is the name of the plugin that reported the findings.
+	Plugin string `json:"plugin"`
+	// Fingerprint is the stable fingerprint of the expression.
+	Fingerprint string `json:"fingerprint"`
+	// Count is the number of the findings.
+	Count int `json:"count"`
+}
+
+// NewBaseline creates an empty baseline.
+func NewBaseline() *Baseline {
+	return &Baseline{
+		Entries: []BaselineEntry{},
+		counts:  map[BaselineEntry]int{},
+	}
+}
+
+// ReadBaseline decodes a baseline that is written by Baseline.Write().
+func ReadBaseline(in io.Reader) (*Baseline, error) {
+	b := NewBaseline()
+	if err := json.NewDecoder(in).Decode(b); err != nil {
+		return nil, err
+	}
+
+	for _, e := range b.Entries {
+		count := e.Count
+		e.Count = 0
+		b.counts[e] += count
+	}
+
+	return b, nil
+}
+
+// Write encodes the baseline in JSON.
+// the entries are sorted so the output is stable.
+func (b *Baseline) Write(out io.Writer) error {
+	b.Entries = make([]BaselineEntry, 0, len(b.counts))
+	for e, count := range b.counts {
+		e.Count = count
+		b.Entries = append(b.Entries, e)
+	}
+	sort.Slice(b.Entries, func(i, j int) bool {
+		l, r := b.Entries[i], b.Entries[j]
+		if l.File != r.File {
+			return l.File < r.File
+		}
+		if l.Rule != r.Rule {
+			return l.Rule < r.Rule
+		}
+		if l.Plugin != r.Plugin {
+			return l.Plugin < r.Plugin
+		}
+		return l.Fingerprint < r.Fingerprint
+	})
+
+	enc := json.NewEncoder(out)
+	enc.SetIndent("", "  ")
+	return enc.Encode(b)
+}
+
+// record adds a finding to the baseline.
+func (b *Baseline) record(key BaselineEntry) {
+	b.counts[key]++
+}
+
+// consume determines whether the baseline has the finding.
+// each entry matches at most Count findings.
+func (b *Baseline) consume(key BaselineEntry) bool {
+	if b.counts[key] <= 0 {
+		return false
+	}
+
+	b.counts[key]--
+	return true
+}
+
+// baselineKey returns the key of the findings in the baseline.
+func baselineKey(ctx *ExprContext, pluginName, fingerprint string) BaselineEntry {
+	return BaselineEntry{
+		File:        ctx.File,
+		Rule:        ctx.Name,
+		Plugin:      pluginName,
+		Fingerprint: fingerprint,
+	}
+}
+
+// exprFingerprint returns the fingerprint of the expression.
+// the expression is normalized so the differences of the spaces and the comments are ignored.
+func exprFingerprint(expr parser.Expr) string {
+	sum := sha256.Sum256([]byte(expr.String()))
+	return hex.EncodeToString(sum[:8])
+}
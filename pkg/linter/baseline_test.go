@@ -0,0 +1,85 @@
+/*
+MIT License
+
+# Copyright (c) 2022 Drumato
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package linter_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/Drumato/promqlinter/pkg/linter"
+	"github.com/Drumato/promqlinter/pkg/linter/plugin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBaseline(t *testing.T) {
+	ctx := &linter.ExprContext{Kind: linter.ExprKindAlertingRule, Name: "Down", File: "rules.yaml", Line: 10}
+	lint := func(rawExpr string, options ...linter.PromQLinterOption) string {
+		out := &bytes.Buffer{}
+		options = append(
+			options,
+			linter.WithOutStream(out),
+			linter.WithPlugins(plugin.NewLabelMatcherPlugin(linter.PromQLinterColorModeDisable)),
+		)
+
+		_, err := linter.New(options...).ExecuteWithContext(rawExpr, ctx, linter.DiagnosticLevelInfo)
+		assert.NoError(t, err)
+		return out.String()
+	}
+
+	const legacy = `up{job="a", job="b"} == 0 or up{job="c", job="d"} == 0`
+
+	recorder := linter.NewBaseline()
+	assert.Empty(t, lint(legacy, linter.WithBaselineRecorder(recorder)))
+
+	written := &bytes.Buffer{}
+	assert.NoError(t, recorder.Write(written))
+	assert.Len(t, recorder.Entries, 1)
+	assert.Equal(t, "rules.yaml", recorder.Entries[0].File)
+	assert.Equal(t, "Down", recorder.Entries[0].Rule)
+	assert.Equal(t, "label-matchers", recorder.Entries[0].Plugin)
+	assert.Equal(t, 2, recorder.Entries[0].Count)
+
+	read := func() *linter.Baseline {
+		b, err := linter.ReadBaseline(bytes.NewReader(written.Bytes()))
+		assert.NoError(t, err)
+		return b
+	}
+
+	// the differences of the spaces are ignored.
+	assert.Empty(t, lint(`up{job="a",job="b"} == 0 or up{job="c",job="d"} == 0`, linter.WithBaseline(read())))
+
+	// the findings of the changed expression are reported.
+	out := lint(`up{job="a", job="b"} == 1`, linter.WithBaseline(read()))
+	assert.Contains(t, out, "rules.yaml:10: alerting rule `Down`")
+	assert.Contains(t, out, "contradicts")
+
+	// the findings more than the recorded count are reported.
+	b := read()
+	assert.Empty(t, lint(legacy, linter.WithBaseline(b)))
+	assert.Contains(t, lint(legacy, linter.WithBaseline(b)), "contradicts")
+
+	_, err := linter.ReadBaseline(bytes.NewReader([]byte(`{`)))
+	assert.Error(t, err)
+}
@@ -0,0 +1,80 @@
+/*
+MIT License
+
+# Copyright (c) 2022 Drumato
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package linter
+
+// ExprKind represents where the linted expression comes from.
+type ExprKind uint
+
+const (
+	// ExprKindQuery represents an ad-hoc query like the one given from stdin.
+	ExprKindQuery ExprKind = iota
+	// ExprKindAlertingRule represents the expression of an alerting rule.
+	ExprKindAlertingRule
+	// ExprKindRecordingRule represents the expression of a recording rule.
+	ExprKindRecordingRule
+)
+
+// String implements fmt.Stringer
+func (k ExprKind) String() string {
+	switch k {
+	case ExprKindQuery:
+		return "query"
+	case ExprKindAlertingRule:
+		return "alerting"
+	case ExprKindRecordingRule:
+		return "recording"
+	default:
+		// unreachable
+		return ""
+	}
+}
+
+// ExprContext holds the information around the linted expression.
+type ExprContext struct {
+	// Kind represents where the expression comes from.
+	Kind ExprKind
+	// Name is the alert name or the recorded metric name of the rule.
+	Name string
+	// For is the `for` duration of the alerting rule.
+	For string
+	// KeepFiringFor is the `keep_firing_for` duration of the alerting rule.
+	KeepFiringFor string
+	// File is the path of the manifest that defines the rule.
+	// it's empty for the ad-hoc queries.
+	File string
+	// Line is the line number of the rule in the manifest.
+	Line int
+	// GroupName is the name of the rule group.
+	GroupName string
+	// GroupIndex is the index of the rule group in the manifest.
+	GroupIndex int
+	// GroupInterval is the evaluation interval of the rule group.
+	// it's empty if the group doesn't specify it.
+	GroupInterval string
+	// RuleIndex is the index of the rule in the rule group.
+	RuleIndex int
+	// Comments are the comment lines above the rule in the manifest.
+	Comments []string
+}
@@ -29,6 +29,7 @@ import (
 	"io"
 	"strings"
 
+	"github.com/Drumato/promqlinter/pkg/codes"
 	"github.com/Drumato/promqlinter/pkg/promqlutil"
 	"github.com/fatih/color"
 	"github.com/prometheus/prometheus/promql/parser"
@@ -41,6 +42,7 @@ type Diagnostics interface {
 }
 
 // Diagnostic is the detailed message from linter plugin's rules.
+// the linter also uses the optional interfaces below if the diagnostic implements them.
 type Diagnostic interface {
 	// Level returns the diagnostic level.
 	Level() DiagnosticLevel
@@ -48,6 +50,47 @@ type Diagnostic interface {
 	Report(pluginName string, rawExpr *string, out io.Writer) error
 }
 
+// PositionedDiagnostic is a Diagnostic that knows its position in the expression.
+// the line suppressions only silence the diagnostics that implement it.
+type PositionedDiagnostic interface {
+	Diagnostic
+	// Position returns the position of the diagnostic in the expression.
+	Position() parser.PositionRange
+}
+
+// CodedDiagnostic is a Diagnostic that has the stable code of the check.
+// the severity overrides by codes and the document URLs need it.
+type CodedDiagnostic interface {
+	Diagnostic
+	// Code returns the stable code of the check, e.g., `PQL0101`.
+	// it's empty if the check has no code.
+	Code() string
+}
+
+// FixableDiagnostic is a Diagnostic that may have a suggested fix.
+type FixableDiagnostic interface {
+	Diagnostic
+	// SuggestedFix returns the fix of the diagnostic.
+	// it's nil if the diagnostic has no fix.
+	SuggestedFix() *SuggestedFix
+}
+
+// LevelOverridableDiagnostic is a Diagnostic whose level can be overridden by the severity overrides.
+type LevelOverridableDiagnostic interface {
+	Diagnostic
+	// WithLevel returns a copy of the diagnostic with the given level.
+	WithLevel(level DiagnosticLevel) Diagnostic
+}
+
+// diagnosticCode returns the code of the diagnostic or an empty string if it has no code.
+func diagnosticCode(d Diagnostic) string {
+	if cd, ok := d.(CodedDiagnostic); ok {
+		return cd.Code()
+	}
+
+	return ""
+}
+
 // diagnostics is the default implementation of Diagnostics.
 type diagnostics struct {
 	items []Diagnostic
@@ -75,6 +118,8 @@ type diagnostic struct {
 	level    DiagnosticLevel
 	position parser.PositionRange
 	message  string
+	code     string
+	fix      *SuggestedFix
 	color    PromQLinterColorMode
 }
 
@@ -83,6 +128,40 @@ func (d *diagnostic) Level() DiagnosticLevel {
 	return d.level
 }
 
+// Position implements Diagnostic.
+func (d *diagnostic) Position() parser.PositionRange {
+	return d.position
+}
+
+// Code implements Diagnostic.
+func (d *diagnostic) Code() string {
+	return d.code
+}
+
+// WithCode sets the code of the check to the diagnostic.
+func (d *diagnostic) WithCode(code string) *diagnostic {
+	d.code = code
+	return d
+}
+
+// SuggestedFix implements Diagnostic.
+func (d *diagnostic) SuggestedFix() *SuggestedFix {
+	return d.fix
+}
+
+// WithSuggestedFix sets the fix to the diagnostic.
+func (d *diagnostic) WithSuggestedFix(fix *SuggestedFix) *diagnostic {
+	d.fix = fix
+	return d
+}
+
+// WithLevel implements Diagnostic.
+func (d *diagnostic) WithLevel(level DiagnosticLevel) Diagnostic {
+	copied := *d
+	copied.level = level
+	return &copied
+}
+
 // Report implements Diagnostic.
 func (d *diagnostic) Report(
 	pluginName string,
@@ -95,7 +174,7 @@ func (d *diagnostic) Report(
 		return d.coloredReport(pluginName, rawExpr, out, pos2d)
 	}
 
-	topMsg := fmt.Sprintf("%s<[%s] %s %s", pluginName, d.level.String(), pos2d, d.message)
+	topMsg := fmt.Sprintf("%s<[%s]%s %s %s", pluginName, d.level.String(), d.codeString(), pos2d, d.message)
 	if _, err := fmt.Fprintln(out, topMsg); err != nil {
 		return err
 	}
@@ -118,7 +197,7 @@ func (d *diagnostic) Report(
 		return err
 	}
 
-	return nil
+	return d.reportFix(rawExpr, out, false)
 }
 
 func (d *diagnostic) coloredReport(
@@ -127,7 +206,7 @@ func (d *diagnostic) coloredReport(
 	out io.Writer,
 	pos2d *promqlutil.Source2dPosition,
 ) error {
-	topMsg := fmt.Sprintf("%s<[%s] %s", pluginName, d.level.coloredString(), pos2d)
+	topMsg := fmt.Sprintf("%s<[%s]%s %s", pluginName, d.level.coloredString(), d.codeString(), pos2d)
 	if _, err := fmt.Fprintln(out, topMsg); err != nil {
 		return err
 	}
@@ -153,9 +232,57 @@ func (d *diagnostic) coloredReport(
 		return err
 	}
 
+	return d.reportFix(rawExpr, out, true)
+}
+
+// reportFix outputs the suggested fix as the diff of the changed lines.
+func (d *diagnostic) reportFix(rawExpr *string, out io.Writer, colored bool) error {
+	if d.fix == nil {
+		return nil
+	}
+
+	before, after, line, err := d.fix.changedLines(*rawExpr)
+	if err != nil {
+		return fmt.Errorf("the suggested fix %q: %w", d.fix.Description, err)
+	}
+
+	if _, err := fmt.Fprintf(out, "fix: %s\n", d.fix.Description); err != nil {
+		return err
+	}
+
+	for _, diff := range []struct {
+		sign  string
+		lines []string
+		color func(format string, a ...interface{}) string
+	}{
+		{"-", before, color.HiRedString},
+		{"+", after, color.HiGreenString},
+	} {
+		for i, l := range diff.lines {
+			// diffLine <- "- L1| <the contents at the line>"
+			diffLine := fmt.Sprintf("%s L%d| %s", diff.sign, line+i, l)
+			if colored {
+				diffLine = diff.color("%s", diffLine)
+			}
+
+			if _, err := fmt.Fprintln(out, diffLine); err != nil {
+				return err
+			}
+		}
+	}
+
 	return nil
 }
 
+// codeString returns the code with the leading space for the reports.
+func (d *diagnostic) codeString() string {
+	if d.code == "" {
+		return ""
+	}
+
+	return " " + d.code
+}
+
 func getSpecifiedSubExpr(
 	rawExpr *string,
 	source *parser.PositionRange,
@@ -304,7 +431,7 @@ func convertParseErrorToDiagnostics(
 			e.PositionRange,
 			e.Error(),
 			color,
-		)
+		).WithCode(codes.ParseError)
 
 		ds.Add(d)
 	}
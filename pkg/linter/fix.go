@@ -0,0 +1,105 @@
+/*
+MIT License
+
+# Copyright (c) 2022 Drumato
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package linter
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/prometheus/prometheus/promql/parser"
+)
+
+// SuggestedFix is a fix of the diagnostic that the user can apply to the expression.
+type SuggestedFix struct {
+	// Description describes what the fix does.
+	Description string
+	// Edits are the text edits of the fix.
+	// the edits must not overlap each other.
+	Edits []TextEdit
+}
+
+// TextEdit replaces the text at the position in the expression.
+type TextEdit struct {
+	// Position is the range of the text to be replaced.
+	Position parser.PositionRange
+	// NewText is the replacement. the edit removes the text if it's empty.
+	NewText string
+}
+
+// NewReplaceFix creates a fix that replaces the text at the position with newText.
+func NewReplaceFix(description string, position parser.PositionRange, newText string) *SuggestedFix {
+	return &SuggestedFix{
+		Description: description,
+		Edits:       []TextEdit{{Position: position, NewText: newText}},
+	}
+}
+
+// Apply returns the expression that the fix is applied to.
+func (f *SuggestedFix) Apply(rawExpr string) (string, error) {
+	edits := make([]TextEdit, len(f.Edits))
+	copy(edits, f.Edits)
+	sort.Slice(edits, func(i, j int) bool { return edits[i].Position.Start < edits[j].Position.Start })
+
+	fixed := ""
+	last := 0
+	for _, e := range edits {
+		start, end := int(e.Position.Start), int(e.Position.End)
+		if start < last || start > end || end > len(rawExpr) {
+			return "", fmt.Errorf("the edit at %d-%d is out of range or overlaps another edit", start, end)
+		}
+
+		fixed += rawExpr[last:start] + e.NewText
+		last = end
+	}
+
+	return fixed + rawExpr[last:], nil
+}
+
+// changedLines returns the lines of the expression that the fix changes
+// and the corresponding lines of the fixed expression.
+// the lines are 1-origin.
+func (f *SuggestedFix) changedLines(rawExpr string) (before []string, after []string, line int, err error) {
+	fixed, err := f.Apply(rawExpr)
+	if err != nil {
+		return nil, nil, 0, err
+	}
+
+	beforeLines := strings.Split(rawExpr, "\n")
+	afterLines := strings.Split(fixed, "\n")
+
+	// the common leading/trailing lines are omitted.
+	head := 0
+	for head < len(beforeLines) && head < len(afterLines) && beforeLines[head] == afterLines[head] {
+		head++
+	}
+	tail := 0
+	for tail < len(beforeLines)-head && tail < len(afterLines)-head &&
+		beforeLines[len(beforeLines)-1-tail] == afterLines[len(afterLines)-1-tail] {
+		tail++
+	}
+
+	return beforeLines[head : len(beforeLines)-tail], afterLines[head : len(afterLines)-tail], head + 1, nil
+}
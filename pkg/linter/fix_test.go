@@ -0,0 +1,98 @@
+/*
+MIT License
+
+# Copyright (c) 2022 Drumato
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package linter_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/Drumato/promqlinter/pkg/linter"
+	"github.com/prometheus/prometheus/promql/parser"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSuggestedFixApply(t *testing.T) {
+	tests := []struct {
+		rawExpr  string
+		fix      *linter.SuggestedFix
+		expected string
+		err      bool
+	}{
+		{
+			rawExpr:  `up{job=~"api"}`,
+			fix:      linter.NewReplaceFix("", parser.PositionRange{Start: 0, End: 14}, `up{job="api"}`),
+			expected: `up{job="api"}`,
+		},
+		{
+			rawExpr: `a + b`,
+			fix: &linter.SuggestedFix{Edits: []linter.TextEdit{
+				{Position: parser.PositionRange{Start: 4, End: 5}, NewText: "y"},
+				{Position: parser.PositionRange{Start: 0, End: 1}, NewText: "x"},
+			}},
+			expected: `x + y`,
+		},
+		{
+			rawExpr:  `(up)`,
+			fix:      &linter.SuggestedFix{Edits: []linter.TextEdit{{Position: parser.PositionRange{Start: 0, End: 1}}}},
+			expected: `up)`,
+		},
+		{
+			rawExpr: `a + b`,
+			fix: &linter.SuggestedFix{Edits: []linter.TextEdit{
+				{Position: parser.PositionRange{Start: 0, End: 3}, NewText: "x"},
+				{Position: parser.PositionRange{Start: 2, End: 5}, NewText: "y"},
+			}},
+			err: true,
+		},
+		{
+			rawExpr: `up`,
+			fix:     linter.NewReplaceFix("", parser.PositionRange{Start: 0, End: 10}, `x`),
+			err:     true,
+		},
+	}
+
+	for _, tt := range tests {
+		fixed, err := tt.fix.Apply(tt.rawExpr)
+		if tt.err {
+			assert.Error(t, err, tt.rawExpr)
+			continue
+		}
+
+		assert.NoError(t, err, tt.rawExpr)
+		assert.Equal(t, tt.expected, fixed)
+	}
+}
+
+func TestReportSuggestedFix(t *testing.T) {
+	const rawExpr = "sum(\n  up{job=~\"^api$\"}\n)"
+
+	d := linter.NoncoloredInfoDiagnostic(parser.PositionRange{Start: 7, End: 23}, "the anchors are redundant").
+		WithSuggestedFix(linter.NewReplaceFix("remove the anchors", parser.PositionRange{Start: 7, End: 23}, `up{job=~"api"}`))
+
+	out := &bytes.Buffer{}
+	s := rawExpr
+	assert.NoError(t, d.Report("regex-matchers", &s, out))
+	assert.Contains(t, out.String(), "fix: remove the anchors\n- L2|   up{job=~\"^api$\"}\n+ L2|   up{job=~\"api\"}\n")
+}
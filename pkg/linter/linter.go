@@ -25,9 +25,11 @@ SOFTWARE.
 package linter
 
 import (
+	"fmt"
 	"io"
 
-	"github.com/prometheus/prometheus/promql/parser"
+	"github.com/Drumato/promqlinter/pkg/codes"
+	"github.com/Drumato/promqlinter/pkg/promqlutil"
 )
 
 // PromQLinter is the actual PromQL linter.
@@ -37,6 +39,16 @@ type PromQLinter struct {
 
 	plugins []PromQLinterPlugin
 	color   PromQLinterColorMode
+
+	// baseline is the known findings that are not reported.
+	baseline *Baseline
+	// baselineRecorder records all the findings instead of reporting them.
+	baselineRecorder *Baseline
+	// severityOverrides changes the levels of the diagnostics.
+	severityOverrides []SeverityOverride
+	// docURLTemplate is the template of the document URL of the checks.
+	// see codes.DocURL() for the details.
+	docURLTemplate string
 }
 
 // PromQLinterOption enables the initialization of the PromQLinter by FOP(Functional-Options-Pattern)
@@ -52,7 +64,8 @@ const (
 // New creates a new PromQLinter.
 func New(options ...PromQLinterOption) *PromQLinter {
 	pq := &PromQLinter{
-		plugins: make([]PromQLinterPlugin, 0),
+		plugins:        make([]PromQLinterPlugin, 0),
+		docURLTemplate: codes.DefaultDocURLTemplate,
 	}
 	for _, opt := range options {
 		opt(pq)
@@ -78,14 +91,25 @@ func (r PromQLintResult) Failed() bool {
 func (pq *PromQLinter) Execute(
 	rawExpr string,
 	filter DiagnosticLevel,
+) (PromQLintResult, error) {
+	return pq.ExecuteWithContext(rawExpr, &ExprContext{Kind: ExprKindQuery}, filter)
+}
+
+// ExecuteWithContext starts the lint process with the context of the expression.
+// the result is failed if any diagnostic is reported after the suppressions, the baseline and the filter.
+// see Execute() for the other parameters.
+func (pq *PromQLinter) ExecuteWithContext(
+	rawExpr string,
+	ctx *ExprContext,
+	filter DiagnosticLevel,
 ) (PromQLintResult, error) {
 	ok := true
-	expr, err := parser.ParseExpr(rawExpr)
+	expr, err := promqlutil.ParseExpr(rawExpr)
 	parserDs := convertParseErrorToDiagnostics(err, pq.color)
 	if parserDs != nil {
 		for _, d := range parserDs.Slice() {
 			if d.Level() >= filter {
-				if err := d.Report("promql/parser", &rawExpr, pq.outStream); err != nil {
+				if err := pq.report(ctx, ok, "promql/parser", &rawExpr, d); err != nil {
 					return PromQLintResultFailed, err
 				}
 				ok = false
@@ -97,15 +121,38 @@ func (pq *PromQLinter) Execute(
 		return PromQLintResultFailed, nil
 	}
 
+	pluginNames := map[string]struct{}{}
 	for _, p := range pq.plugins {
-		ds, err := p.Execute(expr)
+		pluginNames[p.Name()] = struct{}{}
+	}
+	suppressions, suppressionDs := collectSuppressions(rawExpr, ctx, pluginNames, pq.color)
+	fingerprint := exprFingerprint(expr)
+
+	for _, p := range pq.plugins {
+		var ds Diagnostics
+		if cp, isContextPlugin := p.(PromQLinterContextPlugin); isContextPlugin {
+			ds, err = cp.ExecuteWithContext(expr, ctx)
+		} else {
+			ds, err = p.Execute(expr)
+		}
 		if err != nil {
 			return PromQLintResultFailed, err
 		}
 
 		for _, d := range ds.Slice() {
+			d, enabled := overrideSeverity(pq.severityOverrides, p.Name(), ctx, d)
+			if !enabled {
+				continue
+			}
+			if suppressed(suppressions, p.Name(), &rawExpr, d) {
+				continue
+			}
+
 			if d.Level() >= filter {
-				if err := d.Report(p.Name(), &rawExpr, pq.outStream); err != nil {
+				if pq.baselined(baselineKey(ctx, p.Name(), fingerprint)) {
+					continue
+				}
+				if err := pq.report(ctx, ok, p.Name(), &rawExpr, d); err != nil {
 					return PromQLintResultFailed, err
 				}
 				ok = false
@@ -113,9 +160,77 @@ func (pq *PromQLinter) Execute(
 		}
 	}
 
+	suppressionDs = append(suppressionDs, unusedSuppressionDiagnostics(suppressions, pq.color)...)
+	for _, d := range suppressionDs {
+		d, enabled := overrideSeverity(pq.severityOverrides, suppressionPluginName, ctx, d)
+		if !enabled {
+			continue
+		}
+
+		if d.Level() >= filter {
+			if pq.baselined(baselineKey(ctx, suppressionPluginName, fingerprint)) {
+				continue
+			}
+			if err := pq.report(ctx, ok, suppressionPluginName, &rawExpr, d); err != nil {
+				return PromQLintResultFailed, err
+			}
+			ok = false
+		}
+	}
+
+	if !ok {
+		return PromQLintResultFailed, nil
+	}
+
 	return PromQLintResultOK, nil
 }
 
+// baselined determines whether the finding is in the baseline.
+// all the findings are regarded as baselined while recording the baseline.
+func (pq *PromQLinter) baselined(key BaselineEntry) bool {
+	if pq.baselineRecorder != nil {
+		pq.baselineRecorder.record(key)
+		return true
+	}
+
+	return pq.baseline != nil && pq.baseline.consume(key)
+}
+
+// report outputs the diagnostic with the location of the rule and the document URL of the check.
+func (pq *PromQLinter) report(
+	ctx *ExprContext,
+	first bool,
+	pluginName string,
+	rawExpr *string,
+	d Diagnostic,
+) error {
+	if err := pq.reportLocation(ctx, first); err != nil {
+		return err
+	}
+	if err := d.Report(pluginName, rawExpr, pq.outStream); err != nil {
+		return err
+	}
+
+	if url := codes.DocURL(pq.docURLTemplate, diagnosticCode(d)); url != "" {
+		if _, err := fmt.Fprintf(pq.outStream, "see %s\n", url); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// reportLocation outputs the location of the rule before the first diagnostic of the expression.
+// nothing is output for the expressions that don't come from any file.
+func (pq *PromQLinter) reportLocation(ctx *ExprContext, first bool) error {
+	if !first || ctx.File == "" {
+		return nil
+	}
+
+	_, err := fmt.Fprintf(pq.outStream, "%s:%d: %s rule `%s`\n", ctx.File, ctx.Line, ctx.Kind, ctx.Name)
+	return err
+}
+
 // WithPlugins sets the set of the linter plugin to the linter.
 // Note that this function should be called before WithPlugin().
 // Because this function updates the plugin set entirely.
@@ -139,6 +254,38 @@ func WithOutStream(out io.Writer) PromQLinterOption {
 	}
 }
 
+// WithBaseline sets the baseline whose findings are not reported.
+func WithBaseline(b *Baseline) PromQLinterOption {
+	return func(pq *PromQLinter) {
+		pq.baseline = b
+	}
+}
+
+// WithBaselineRecorder makes the linter record all the findings to the given baseline
+// instead of reporting them.
+func WithBaselineRecorder(b *Baseline) PromQLinterOption {
+	return func(pq *PromQLinter) {
+		pq.baselineRecorder = b
+	}
+}
+
+// WithSeverityOverrides sets the overrides of the diagnostic levels.
+// the overrides are applied before the diagnostics are filtered.
+func WithSeverityOverrides(overrides ...SeverityOverride) PromQLinterOption {
+	return func(pq *PromQLinter) {
+		pq.severityOverrides = overrides
+	}
+}
+
+// WithDocURLTemplate sets the template of the document URL that is reported with the diagnostics.
+// `{code}` in the template is replaced with the code of the check.
+// the URL is not reported if the template is empty.
+func WithDocURLTemplate(template string) PromQLinterOption {
+	return func(pq *PromQLinter) {
+		pq.docURLTemplate = template
+	}
+}
+
 // WithANSIColorMode sets the color mode to the linter.
 func WithANSIColorMode(mode PromQLinterColorMode) PromQLinterOption {
 	return func(pq *PromQLinter) {
@@ -0,0 +1,119 @@
+/*
+MIT License
+
+# Copyright (c) 2022 Drumato
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package linter_test
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/Drumato/promqlinter/pkg/linter"
+	"github.com/Drumato/promqlinter/pkg/linter/plugin"
+	"github.com/prometheus/prometheus/promql/parser"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExecuteResult(t *testing.T) {
+	tests := []struct {
+		expr     string
+		filter   linter.DiagnosticLevel
+		expected linter.PromQLintResult
+	}{
+		{`up`, linter.DiagnosticLevelInfo, linter.PromQLintResultOK},
+		{`up{`, linter.DiagnosticLevelInfo, linter.PromQLintResultFailed},
+		{`up{job="a", job="b"}`, linter.DiagnosticLevelInfo, linter.PromQLintResultFailed},
+		{`up{job="a", job="b"} # promqlinter:ignore label-matchers the selector is generated`, linter.DiagnosticLevelInfo, linter.PromQLintResultOK},
+		{`up{job=~"^api-.+$"}`, linter.DiagnosticLevelInfo, linter.PromQLintResultFailed},
+		{`up{job=~"^api-.+$"}`, linter.DiagnosticLevelWarning, linter.PromQLintResultOK},
+	}
+
+	for _, tt := range tests {
+		l := linter.New(
+			linter.WithOutStream(&bytes.Buffer{}),
+			linter.WithANSIColorMode(linter.PromQLinterColorModeDisable),
+			linter.WithPlugins(plugin.DefaultsWithConfig(plugin.DefaultConfig(), linter.PromQLinterColorModeDisable)...),
+		)
+
+		result, err := l.Execute(tt.expr, tt.filter)
+		assert.NoError(t, err)
+		assert.Equal(t, tt.expected, result, tt.expr)
+	}
+}
+
+// minimalDiagnostic implements the required methods of linter.Diagnostic only.
+type minimalDiagnostic struct{}
+
+func (minimalDiagnostic) Level() linter.DiagnosticLevel {
+	return linter.DiagnosticLevelWarning
+}
+
+func (minimalDiagnostic) Report(pluginName string, rawExpr *string, out io.Writer) error {
+	_, err := fmt.Fprintf(out, "%s<minimal\n", pluginName)
+	return err
+}
+
+type minimalPlugin struct{}
+
+func (minimalPlugin) Name() string {
+	return "minimal"
+}
+
+func (minimalPlugin) Execute(expr parser.Expr) (linter.Diagnostics, error) {
+	ds := linter.NewDiagnostics()
+	ds.Add(minimalDiagnostic{})
+	return ds, nil
+}
+
+func TestExecuteMinimalDiagnostic(t *testing.T) {
+	tests := []struct {
+		overrides []linter.SeverityOverride
+		comments  []string
+		expected  linter.PromQLintResult
+	}{
+		{nil, nil, linter.PromQLintResultFailed},
+		// the level can't be overridden without LevelOverridableDiagnostic.
+		{[]linter.SeverityOverride{{Plugin: "minimal", Level: "info"}}, nil, linter.PromQLintResultFailed},
+		{[]linter.SeverityOverride{{Plugin: "minimal", Level: linter.SeverityOff}}, nil, linter.PromQLintResultOK},
+		{nil, []string{"# promqlinter:ignore minimal the reason"}, linter.PromQLintResultOK},
+	}
+
+	for _, tt := range tests {
+		out := &bytes.Buffer{}
+		l := linter.New(
+			linter.WithOutStream(out),
+			linter.WithPlugin(minimalPlugin{}),
+			linter.WithSeverityOverrides(tt.overrides...),
+		)
+
+		ctx := &linter.ExprContext{Kind: linter.ExprKindQuery, Comments: tt.comments}
+		result, err := l.ExecuteWithContext(`up`, ctx, linter.DiagnosticLevelWarning)
+		assert.NoError(t, err)
+		assert.Equal(t, tt.expected, result)
+		if tt.expected.Failed() {
+			assert.Equal(t, "minimal<minimal\n", out.String())
+		}
+	}
+}
@@ -34,3 +34,11 @@ type PromQLinterPlugin interface {
 	// Execute lints the PromQL expression.
 	Execute(expr parser.Expr) (Diagnostics, error)
 }
+
+// PromQLinterContextPlugin is an interface for the plugins that need the context of the expression.
+// the linter calls ExecuteWithContext() instead of Execute() if a plugin implements this interface.
+type PromQLinterContextPlugin interface {
+	PromQLinterPlugin
+	// ExecuteWithContext lints the PromQL expression with its context.
+	ExecuteWithContext(expr parser.Expr, ctx *ExprContext) (Diagnostics, error)
+}
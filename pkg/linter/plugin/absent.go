@@ -0,0 +1,141 @@
+/*
+MIT License
+
+# Copyright (c) 2022 Drumato
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package plugin
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/Drumato/promqlinter/pkg/codes"
+	"github.com/Drumato/promqlinter/pkg/linter"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/promql/parser"
+)
+
+type absent struct {
+	color linter.PromQLinterColorMode
+}
+
+// Execute implements linter.PromQLinterPlugin
+func (a *absent) Execute(expr parser.Expr) (linter.Diagnostics, error) {
+	return a.ExecuteWithContext(expr, &linter.ExprContext{Kind: linter.ExprKindQuery})
+}
+
+// ExecuteWithContext implements linter.PromQLinterContextPlugin
+func (a *absent) ExecuteWithContext(
+	expr parser.Expr,
+	ctx *linter.ExprContext,
+) (linter.Diagnostics, error) {
+	ds := linter.NewDiagnostics()
+	parser.Inspect(expr, func(n parser.Node, path []parser.Node) error {
+		switch node := n.(type) {
+		case *parser.Call:
+			if node.Func.Name != "absent" && node.Func.Name != "absent_over_time" {
+				return nil
+			}
+
+			for _, d := range a.checkCall(node, ctx) {
+				ds.Add(d)
+			}
+
+			return nil
+		default:
+			// traverse all the non-nil children.
+			return nil
+		}
+	})
+
+	return ds, nil
+}
+
+// checkCall checks an absent()/absent_over_time() call.
+func (a *absent) checkCall(node *parser.Call, ctx *linter.ExprContext) []linter.Diagnostic {
+	ds := []linter.Diagnostic{}
+	name := node.Func.Name
+
+	arg := unwrapParenExpr(node.Args[0])
+	if ms, ok := arg.(*parser.MatrixSelector); ok {
+		arg = ms.VectorSelector
+	}
+
+	vs, ok := arg.(*parser.VectorSelector)
+	if !ok {
+		msg := fmt.Sprintf(
+			"`%s` of `%s` returns a series without any labels; apply `%s` to the series selector instead",
+			name, node.Args[0], name,
+		)
+		ds = append(ds, linter.WarningDiagnostic(node.PosRange, msg, a.color).WithCode(codes.AbsentWithoutSelector))
+	} else {
+		carried := []string{}
+		dropped := []string{}
+		for _, lm := range vs.LabelMatchers {
+			// the label with the empty value doesn't exist in the result.
+			if lm.Name == labels.MetricName || (lm.Type == labels.MatchEqual && lm.Value == "") {
+				continue
+			}
+
+			if lm.Type == labels.MatchEqual {
+				carried = append(carried, lm.String())
+			} else {
+				dropped = append(dropped, fmt.Sprintf("`%s`", lm))
+			}
+		}
+		sort.Strings(carried)
+
+		msg := fmt.Sprintf("the result of `%s` carries the labels {%s}", name, strings.Join(carried, ", "))
+		ds = append(ds, linter.InfoDiagnostic(node.PosRange, msg, a.color).WithCode(codes.AbsentCarriedLabels))
+
+		if len(dropped) != 0 {
+			msg := fmt.Sprintf(
+				"the result of `%s` doesn't carry the labels of the matchers %s",
+				name, strings.Join(dropped, ", "),
+			)
+			ds = append(ds, linter.WarningDiagnostic(node.PosRange, msg, a.color).WithCode(codes.AbsentDroppedLabels))
+		}
+	}
+
+	if ctx.Kind == linter.ExprKindAlertingRule && ctx.For == "" {
+		msg := fmt.Sprintf(
+			"the alert with `%s` has no `for`; it fires on every scrape failure or restart",
+			name,
+		)
+		ds = append(ds, linter.WarningDiagnostic(node.PosRange, msg, a.color).WithCode(codes.AbsentWithoutFor))
+	}
+
+	return ds
+}
+
+// Name implements linter.PromQLinterPlugin
+func (*absent) Name() string {
+	return "absent"
+}
+
+// NewAbsentPlugin creates an absent plugin.
+func NewAbsentPlugin(
+	color linter.PromQLinterColorMode,
+) linter.PromQLinterContextPlugin {
+	return &absent{color}
+}
@@ -0,0 +1,105 @@
+/*
+MIT License
+
+# Copyright (c) 2022 Drumato
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package plugin_test
+
+import (
+	"testing"
+
+	"github.com/Drumato/promqlinter/pkg/codes"
+	"github.com/Drumato/promqlinter/pkg/linter"
+	"github.com/Drumato/promqlinter/pkg/linter/plugin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAbsent(t *testing.T) {
+	tests := []struct {
+		expr     string
+		ctx      *linter.ExprContext
+		expected []string
+	}{
+		{`up`, &linter.ExprContext{Kind: linter.ExprKindAlertingRule}, nil},
+		{`absent(up{job="node", instance="a"})`, &linter.ExprContext{Kind: linter.ExprKindQuery}, []string{"[INFO]", "the result of `absent` carries the labels {instance=\"a\", job=\"node\"}"}},
+		{`absent(sum(up))`, &linter.ExprContext{Kind: linter.ExprKindQuery}, []string{"[WARN]", "`absent` of `sum(up)` returns a series without any labels"}},
+		{`absent_over_time(up{job=~"node.*"}[5m])`, &linter.ExprContext{Kind: linter.ExprKindQuery}, []string{"[WARN]", "doesn't carry the labels of the matchers `job=~\"node.*\"`"}},
+		{`absent(up{job="node"})`, &linter.ExprContext{Kind: linter.ExprKindAlertingRule}, []string{"[WARN]", "the alert with `absent` has no `for`"}},
+	}
+
+	for _, tt := range tests {
+		p := plugin.NewAbsentPlugin(linter.PromQLinterColorModeDisable)
+		out := pluginTestWithContext(t, tt.expr, tt.ctx, p)
+
+		if tt.expected == nil {
+			assert.Empty(t, out, tt.expr)
+		}
+		for _, s := range tt.expected {
+			assert.Contains(t, out, s, tt.expr)
+		}
+	}
+
+	ctx := &linter.ExprContext{Kind: linter.ExprKindAlertingRule, For: "5m"}
+	out := pluginTestWithContext(t, `absent(up{job="node"})`, ctx, plugin.NewAbsentPlugin(linter.PromQLinterColorModeDisable))
+	assert.NotContains(t, out, "no `for`")
+}
+
+func TestAbsentDiagnostics(t *testing.T) {
+	alerting := &linter.ExprContext{Kind: linter.ExprKindAlertingRule}
+	tests := []struct {
+		expr     string
+		ctx      *linter.ExprContext
+		expected []reported
+	}{
+		{`sum(absent(up{job="a"}))`, nil, []reported{{codes.AbsentCarriedLabels, linter.DiagnosticLevelInfo, `absent(up{job="a"})`}}},
+		{`absent(rate(up[5m]))`, nil, []reported{{codes.AbsentWithoutSelector, linter.DiagnosticLevelWarning, `absent(rate(up[5m]))`}}},
+		{
+			`absent_over_time(up{job=~"node.*"}[5m])`,
+			nil,
+			[]reported{
+				{codes.AbsentCarriedLabels, linter.DiagnosticLevelInfo, `absent_over_time(up{job=~"node.*"}[5m])`},
+				{codes.AbsentDroppedLabels, linter.DiagnosticLevelWarning, `absent_over_time(up{job=~"node.*"}[5m])`},
+			},
+		},
+		{
+			`up == 0 or absent(up)`,
+			alerting,
+			[]reported{
+				{codes.AbsentCarriedLabels, linter.DiagnosticLevelInfo, `absent(up)`},
+				{codes.AbsentWithoutFor, linter.DiagnosticLevelWarning, `absent(up)`},
+			},
+		},
+		{`absent(up)`, &linter.ExprContext{Kind: linter.ExprKindAlertingRule, For: "5m"}, []reported{{codes.AbsentCarriedLabels, linter.DiagnosticLevelInfo, `absent(up)`}}},
+		{`absent_over_time(up[5m]) == 0`, nil, []reported{{codes.AbsentCarriedLabels, linter.DiagnosticLevelInfo, `absent_over_time(up[5m])`}}},
+		{`present_over_time(up[5m])`, alerting, []reported{}},
+	}
+
+	for _, tt := range tests {
+		p := plugin.NewAbsentPlugin(linter.PromQLinterColorModeDisable)
+		assert.Equal(t, tt.expected, pluginDiagnostics(t, tt.expr, tt.ctx, p), tt.expr)
+	}
+
+	// the label with the empty value is neither carried nor dropped.
+	out := pluginTest(t, `absent(up{job="", env="prod"})`, plugin.NewAbsentPlugin(linter.PromQLinterColorModeDisable))
+	assert.Contains(t, out, "the result of `absent` carries the labels {env=\"prod\"}")
+	assert.NotContains(t, out, "PQL1103")
+}
@@ -0,0 +1,210 @@
+/*
+MIT License
+
+# Copyright (c) 2022 Drumato
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package plugin
+
+import (
+	"fmt"
+
+	"github.com/Drumato/promqlinter/pkg/codes"
+	"github.com/Drumato/promqlinter/pkg/linter"
+	"github.com/prometheus/prometheus/promql/parser"
+)
+
+const (
+	// AggregationGroupingPolicyBy requires `by` so the output labels are explicit.
+	AggregationGroupingPolicyBy = "by"
+	// AggregationGroupingPolicyWithout requires `without` to keep the topology labels.
+	AggregationGroupingPolicyWithout = "without"
+)
+
+// AggregationGroupingConfig is the policy of the aggregation-grouping plugin.
+type AggregationGroupingConfig struct {
+	// Policy is the required grouping modifier (`by` or `without`).
+	// both modifiers are allowed if it's empty.
+	Policy string `json:"policy,omitempty"`
+}
+
+// Validate checks the policy is either `by` or `without`.
+func (c AggregationGroupingConfig) Validate() error {
+	switch c.Policy {
+	case "", AggregationGroupingPolicyBy, AggregationGroupingPolicyWithout:
+		return nil
+	default:
+		return fmt.Errorf("invalid aggregation grouping policy %q", c.Policy)
+	}
+}
+
+type aggregationGrouping struct {
+	config                AggregationGroupingConfig
+	highCardinalityLabels []string
+	// err is the error of validating the policy.
+	err   error
+	color linter.PromQLinterColorMode
+}
+
+// Execute implements linter.PromQLinterPlugin
+func (a *aggregationGrouping) Execute(expr parser.Expr) (linter.Diagnostics, error) {
+	if a.err != nil {
+		return nil, a.err
+	}
+
+	ds := linter.NewDiagnostics()
+	parser.Inspect(expr, func(n parser.Node, path []parser.Node) error {
+		switch node := n.(type) {
+		case *parser.AggregateExpr:
+			for _, d := range a.checkGrouping(node) {
+				ds.Add(d)
+			}
+
+			return nil
+		default:
+			// traverse all the non-nil children.
+			return nil
+		}
+	})
+
+	return ds, nil
+}
+
+// checkGrouping checks the `by`/`without` modifier of an aggregation.
+func (a *aggregationGrouping) checkGrouping(node *parser.AggregateExpr) []linter.Diagnostic {
+	ds := []linter.Diagnostic{}
+	if node.Grouping == nil && !node.Without {
+		// no modifiers are given.
+		return ds
+	}
+
+	modifier := AggregationGroupingPolicyBy
+	if node.Without {
+		modifier = AggregationGroupingPolicyWithout
+	}
+
+	if len(node.Grouping) == 0 {
+		var (
+			msg string
+			fix *linter.SuggestedFix
+		)
+		if node.Without {
+			msg = fmt.Sprintf(
+				"`%s without ()` keeps all the labels except the metric name, so it aggregates nothing; "+
+					"remove `%s without ()` if you mean to keep the labels, or list the labels to aggregate away",
+				node.Op, node.Op,
+			)
+		} else {
+			msg = fmt.Sprintf("`%s by ()` is the same as `%s` without grouping; remove the empty grouping", node.Op, node.Op)
+			fix = regroupFix("remove the empty grouping", node, nil)
+		}
+
+		return append(ds, linter.WarningDiagnostic(node.PosRange, msg, a.color).WithCode(codes.EmptyGrouping).WithSuggestedFix(fix))
+	}
+
+	if a.config.Policy != "" && a.config.Policy != modifier {
+		msg := fmt.Sprintf(
+			"`%s` uses `%s` but the policy requires `%s`",
+			node.Op, modifier, a.config.Policy,
+		)
+		ds = append(ds, linter.WarningDiagnostic(node.PosRange, msg, a.color).WithCode(codes.GroupingPolicy))
+	}
+
+	seen := map[string]struct{}{}
+	unique := make([]string, 0, len(node.Grouping))
+	duplicated := []string{}
+	for _, name := range node.Grouping {
+		if _, ok := seen[name]; ok {
+			duplicated = append(duplicated, name)
+			continue
+		}
+		seen[name] = struct{}{}
+		unique = append(unique, name)
+	}
+
+	for _, name := range highCardinalityGrouping(node, a.highCardinalityLabels) {
+		msg := fmt.Sprintf(
+			"`%s` groups by the high-cardinality label `%s`; the result may have too many series",
+			node.Op, name,
+		)
+		ds = append(ds, linter.WarningDiagnostic(node.PosRange, msg, a.color).WithCode(codes.HighCardinalityGrouping))
+	}
+
+	for _, name := range duplicated {
+		msg := fmt.Sprintf("the label `%s` is duplicated in the grouping of `%s`", name, node.Op)
+		fix := regroupFix(fmt.Sprintf("remove the duplicated `%s`", name), node, unique)
+		ds = append(ds, linter.WarningDiagnostic(node.PosRange, msg, a.color).WithCode(codes.DuplicateGroupingLabel).WithSuggestedFix(fix))
+	}
+
+	return ds
+}
+
+// highCardinalityGrouping returns the high-cardinality labels that the aggregation groups `by`.
+func highCardinalityGrouping(node *parser.AggregateExpr, highCardinalityLabels []string) []string {
+	grouped := []string{}
+	if node.Without {
+		return grouped
+	}
+
+	for _, name := range highCardinalityLabels {
+		for _, l := range node.Grouping {
+			if l == name {
+				grouped = append(grouped, name)
+				break
+			}
+		}
+	}
+
+	return grouped
+}
+
+// regroupFix suggests replacing the grouping of the aggregation.
+// the grouping is removed if it's empty.
+func regroupFix(description string, node *parser.AggregateExpr, grouping []string) *linter.SuggestedFix {
+	regrouped := *node
+	regrouped.Grouping = grouping
+	if len(grouping) == 0 {
+		regrouped.Without = false
+	}
+
+	return linter.NewReplaceFix(description, node.PosRange, regrouped.String())
+}
+
+// Name implements linter.PromQLinterPlugin
+func (*aggregationGrouping) Name() string {
+	return "aggregation-grouping"
+}
+
+// NewAggregationGroupingPlugin creates an aggregation-grouping plugin.
+// the grouping by highCardinalityLabels is also reported.
+// the invalid policy is returned as the error of Execute(); see AggregationGroupingConfig.Validate().
+func NewAggregationGroupingPlugin(
+	config AggregationGroupingConfig,
+	highCardinalityLabels []string,
+	color linter.PromQLinterColorMode,
+) linter.PromQLinterPlugin {
+	return &aggregationGrouping{
+		config:                config,
+		highCardinalityLabels: highCardinalityLabels,
+		err:                   config.Validate(),
+		color:                 color,
+	}
+}
@@ -0,0 +1,110 @@
+/*
+MIT License
+
+# Copyright (c) 2022 Drumato
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package plugin_test
+
+import (
+	"testing"
+
+	"github.com/Drumato/promqlinter/pkg/codes"
+	"github.com/Drumato/promqlinter/pkg/linter"
+	"github.com/Drumato/promqlinter/pkg/linter/plugin"
+	"github.com/prometheus/prometheus/promql/parser"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAggregationGrouping(t *testing.T) {
+	tests := []struct {
+		expr     string
+		policy   string
+		expected []string
+	}{
+		{`sum(up)`, plugin.AggregationGroupingPolicyBy, nil},
+		{`sum by (job) (up)`, plugin.AggregationGroupingPolicyBy, nil},
+		{`sum without (instance) (up)`, "", nil},
+		{`sum without (instance) (up)`, plugin.AggregationGroupingPolicyBy, []string{"[WARN]", "`sum` uses `without` but the policy requires `by`"}},
+		{`max by (job) (up)`, plugin.AggregationGroupingPolicyWithout, []string{"[WARN]", "`max` uses `by` but the policy requires `without`"}},
+		{`sum by () (up)`, "", []string{"[WARN]", "`sum by ()` is the same as `sum` without grouping"}},
+		{`sum without () (up)`, "", []string{"[WARN]", "`sum without ()` keeps all the labels except the metric name"}},
+		{`sum by (job, job) (up)`, "", []string{"[WARN]", "the label `job` is duplicated in the grouping of `sum`"}},
+		{`sum by (job, pod) (up)`, "", []string{"[WARN]", "`sum` groups by the high-cardinality label `pod`"}},
+		{`sum without (pod) (up)`, "", nil},
+	}
+
+	for _, tt := range tests {
+		config := plugin.AggregationGroupingConfig{Policy: tt.policy}
+		p := plugin.NewAggregationGroupingPlugin(config, []string{"pod"}, linter.PromQLinterColorModeDisable)
+		out := pluginTest(t, tt.expr, p)
+
+		if tt.expected == nil {
+			assert.Empty(t, out, tt.expr)
+		}
+		for _, s := range tt.expected {
+			assert.Contains(t, out, s, tt.expr)
+		}
+	}
+
+	// `by ()` has the different meaning from `without ()`.
+	p := plugin.NewAggregationGroupingPlugin(plugin.AggregationGroupingConfig{}, nil, linter.PromQLinterColorModeDisable)
+	assert.NotContains(t, pluginTest(t, `sum without () (up)`, p), "by ()")
+
+	p = plugin.NewAggregationGroupingPlugin(
+		plugin.AggregationGroupingConfig{Policy: "group"},
+		nil,
+		linter.PromQLinterColorModeDisable,
+	)
+	expr, err := parser.ParseExpr(`sum by (job) (up)`)
+	assert.NoError(t, err)
+	_, err = p.Execute(expr)
+	assert.Error(t, err)
+
+	assert.NoError(t, plugin.AggregationGroupingConfig{Policy: plugin.AggregationGroupingPolicyWithout}.Validate())
+	assert.Error(t, plugin.AggregationGroupingConfig{Policy: "group"}.Validate())
+	assert.Error(t, (&plugin.Config{AggregationGrouping: plugin.AggregationGroupingConfig{Policy: "group"}}).Validate())
+}
+
+func TestAggregationGroupingDiagnostics(t *testing.T) {
+	tests := []struct {
+		expr     string
+		policy   string
+		expected []reported
+	}{
+		// the empty grouping doesn't violate the policy.
+		{`sum by () (up)`, plugin.AggregationGroupingPolicyBy, []reported{{codes.EmptyGrouping, linter.DiagnosticLevelWarning, `sum by () (up)`}}},
+		{`sum without () (up)`, "", []reported{{codes.EmptyGrouping, linter.DiagnosticLevelWarning, `sum without () (up)`}}},
+		{`sum without (instance) (up)`, plugin.AggregationGroupingPolicyBy, []reported{{codes.GroupingPolicy, linter.DiagnosticLevelWarning, `sum without (instance) (up)`}}},
+		{`max(sum(x) by (job, job))`, "", []reported{{codes.DuplicateGroupingLabel, linter.DiagnosticLevelWarning, `sum(x) by (job, job)`}}},
+		{`abs(sum by (job, pod) (up))`, "", []reported{{codes.HighCardinalityGrouping, linter.DiagnosticLevelWarning, `sum by (job, pod) (up)`}}},
+		// `without` never keeps more labels than the input.
+		{`sum without (pod) (up)`, "", []reported{}},
+		{`sum(up)`, plugin.AggregationGroupingPolicyBy, []reported{}},
+		{`sum without (instance) (up)`, plugin.AggregationGroupingPolicyWithout, []reported{}},
+	}
+
+	for _, tt := range tests {
+		config := plugin.AggregationGroupingConfig{Policy: tt.policy}
+		p := plugin.NewAggregationGroupingPlugin(config, []string{"pod"}, linter.PromQLinterColorModeDisable)
+		assert.Equal(t, tt.expected, pluginDiagnostics(t, tt.expr, nil, p), tt.expr)
+	}
+}
@@ -0,0 +1,135 @@
+/*
+MIT License
+
+# Copyright (c) 2022 Drumato
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package plugin
+
+import (
+	"fmt"
+
+	"github.com/Drumato/promqlinter/pkg/codes"
+	"github.com/Drumato/promqlinter/pkg/linter"
+	"github.com/Drumato/promqlinter/pkg/promqlutil"
+	"github.com/prometheus/prometheus/promql/parser"
+)
+
+type alertCondition struct {
+	color linter.PromQLinterColorMode
+}
+
+// Execute implements linter.PromQLinterPlugin
+func (a *alertCondition) Execute(expr parser.Expr) (linter.Diagnostics, error) {
+	return a.ExecuteWithContext(expr, &linter.ExprContext{Kind: linter.ExprKindQuery})
+}
+
+// ExecuteWithContext implements linter.PromQLinterContextPlugin
+func (a *alertCondition) ExecuteWithContext(
+	expr parser.Expr,
+	ctx *linter.ExprContext,
+) (linter.Diagnostics, error) {
+	ds := linter.NewDiagnostics()
+	if ctx.Kind != linter.ExprKindAlertingRule {
+		return ds, nil
+	}
+
+	pos := expr.PositionRange()
+	if constant, ok := promqlutil.EvalConstant(expr); ok {
+		if constant.Empty {
+			msg := "the alert expression always returns an empty vector; the alert never fires"
+			ds.Add(linter.WarningDiagnostic(pos, msg, a.color).WithCode(codes.AlertNeverFires))
+			return ds, nil
+		}
+
+		msg := fmt.Sprintf("the alert expression is the constant `%s`; the alert always fires", constant)
+		ds.Add(linter.WarningDiagnostic(pos, msg, a.color).WithCode(codes.AlertAlwaysFires))
+		return ds, nil
+	}
+
+	// the selector-free expressions like `hour() >= 9 < 17` still work as a time window.
+	if len(parser.ExtractSelectors(expr)) == 0 && !filtersSeries(expr) {
+		msg := "the alert expression doesn't select any series; the alert always fires"
+		ds.Add(linter.WarningDiagnostic(pos, msg, a.color).WithCode(codes.AlertAlwaysFires))
+		return ds, nil
+	}
+
+	outermost := unwrapParenExpr(expr)
+	if be, ok := outermost.(*parser.BinaryExpr); ok && be.ReturnBool {
+		msg := "the `bool` comparison returns 0/1 instead of filtering; the alert fires whenever any data exists"
+		ds.Add(linter.WarningDiagnostic(be.PositionRange(), msg, a.color).WithCode(codes.AlertBoolComparison))
+		return ds, nil
+	}
+
+	if !filtersSeries(expr) {
+		msg := "the alert expression has no filter like a comparison, `absent`, `and` or `unless`; the alert fires whenever any data exists"
+		ds.Add(linter.WarningDiagnostic(pos, msg, a.color).WithCode(codes.AlertWithoutFilter))
+	}
+
+	return ds, nil
+}
+
+// Name implements linter.PromQLinterPlugin
+func (*alertCondition) Name() string {
+	return "alert-condition"
+}
+
+// NewAlertConditionPlugin creates an alert-condition plugin.
+func NewAlertConditionPlugin(
+	color linter.PromQLinterColorMode,
+) linter.PromQLinterContextPlugin {
+	return &alertCondition{color}
+}
+
+// unwrapParenExpr removes the parentheses that enclose the expression.
+func unwrapParenExpr(expr parser.Expr) parser.Expr {
+	for {
+		paren, ok := expr.(*parser.ParenExpr)
+		if !ok {
+			return expr
+		}
+		expr = paren.Expr
+	}
+}
+
+// filtersSeries determines whether the outermost operation of the expression depends on the sample values,
+// that is, some series may disappear from the result.
+// the operands of the arithmetic operators, the aggregations and the functions are not considered
+// even if they filter like `(x > 1) * 100` and `count(x > 1)`.
+// such filters usually guard the computation (e.g., the denominator of a ratio) rather than state the alert condition,
+// so the alert condition is expected to be the outermost comparison.
+func filtersSeries(expr parser.Expr) bool {
+	switch e := unwrapParenExpr(expr).(type) {
+	case *parser.BinaryExpr:
+		switch {
+		case e.Op == parser.LAND || e.Op == parser.LUNLESS:
+			return true
+		case e.Op == parser.LOR:
+			return filtersSeries(e.LHS) && filtersSeries(e.RHS)
+		default:
+			return e.Op.IsComparisonOperator() && !e.ReturnBool
+		}
+	case *parser.Call:
+		return e.Func.Name == "absent" || e.Func.Name == "absent_over_time"
+	default:
+		return false
+	}
+}
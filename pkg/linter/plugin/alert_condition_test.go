@@ -0,0 +1,102 @@
+/*
+MIT License
+
+# Copyright (c) 2022 Drumato
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package plugin_test
+
+import (
+	"testing"
+
+	"github.com/Drumato/promqlinter/pkg/codes"
+	"github.com/Drumato/promqlinter/pkg/linter"
+	"github.com/Drumato/promqlinter/pkg/linter/plugin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAlertCondition(t *testing.T) {
+	tests := []struct {
+		expr     string
+		kind     linter.ExprKind
+		expected []string
+	}{
+		{`sum(rate(x[5m]))`, linter.ExprKindQuery, nil},
+		{`sum(rate(x[5m]))`, linter.ExprKindRecordingRule, nil},
+		{`sum(rate(x[5m])) > 10`, linter.ExprKindAlertingRule, nil},
+		{`(sum by (job) (rate(errors[5m])) / sum by (job) (rate(requests[5m]) > 0)) * 100 > 5`, linter.ExprKindAlertingRule, nil},
+		{`absent(up{job="node"})`, linter.ExprKindAlertingRule, nil},
+		{`up unless on (job) maintenance`, linter.ExprKindAlertingRule, nil},
+		{`up == 0 or absent(up)`, linter.ExprKindAlertingRule, nil},
+		{`sum(rate(x[5m]))`, linter.ExprKindAlertingRule, []string{"[WARN]", "the alert expression has no filter"}},
+		{`up == 0 or up`, linter.ExprKindAlertingRule, []string{"the alert expression has no filter"}},
+		{`(sum by (job) (rate(errors[5m])) / sum by (job) (rate(requests[5m]) > 0)) * 100`, linter.ExprKindAlertingRule, []string{"the alert expression has no filter"}},
+		{`sum(rate(x[5m]) > 10)`, linter.ExprKindAlertingRule, []string{"the alert expression has no filter"}},
+		{`((up == 0) or (absent(up)))`, linter.ExprKindAlertingRule, nil},
+		{`(up == bool 0)`, linter.ExprKindAlertingRule, []string{"the `bool` comparison returns 0/1 instead of filtering"}},
+		{`vector(1)`, linter.ExprKindAlertingRule, []string{"the alert expression is the constant `vector(1)`; the alert always fires"}},
+		{`vector(1) < 0`, linter.ExprKindAlertingRule, []string{"always returns an empty vector; the alert never fires"}},
+		{`vector(time())`, linter.ExprKindAlertingRule, []string{"doesn't select any series; the alert always fires"}},
+		{`hour() >= 9 < 17`, linter.ExprKindAlertingRule, nil},
+		{`hour() + 1`, linter.ExprKindAlertingRule, []string{"doesn't select any series; the alert always fires"}},
+		{`(x > 1) * 100`, linter.ExprKindAlertingRule, []string{"the alert expression has no filter"}},
+		{`count(x > 1)`, linter.ExprKindAlertingRule, []string{"the alert expression has no filter"}},
+	}
+
+	for _, tt := range tests {
+		p := plugin.NewAlertConditionPlugin(linter.PromQLinterColorModeDisable)
+		out := pluginTestWithContext(t, tt.expr, &linter.ExprContext{Kind: tt.kind}, p)
+
+		if tt.expected == nil {
+			assert.Empty(t, out, tt.expr)
+		}
+		for _, s := range tt.expected {
+			assert.Contains(t, out, s, tt.expr)
+		}
+	}
+}
+
+func TestAlertConditionDiagnostics(t *testing.T) {
+	tests := []struct {
+		expr     string
+		kind     linter.ExprKind
+		expected []reported
+	}{
+		{`up == 0 or up`, linter.ExprKindAlertingRule, []reported{{codes.AlertWithoutFilter, linter.DiagnosticLevelWarning, `up == 0 or up`}}},
+		{`count(x > 1)`, linter.ExprKindAlertingRule, []reported{{codes.AlertWithoutFilter, linter.DiagnosticLevelWarning, `count(x > 1)`}}},
+		// the parentheses are not pointed.
+		{`(up == bool 0)`, linter.ExprKindAlertingRule, []reported{{codes.AlertBoolComparison, linter.DiagnosticLevelWarning, `up == bool 0`}}},
+		{`vector(1)`, linter.ExprKindAlertingRule, []reported{{codes.AlertAlwaysFires, linter.DiagnosticLevelWarning, `vector(1)`}}},
+		{`vector(1) < 0`, linter.ExprKindAlertingRule, []reported{{codes.AlertNeverFires, linter.DiagnosticLevelWarning, `vector(1) < 0`}}},
+		{`hour() + 1`, linter.ExprKindAlertingRule, []reported{{codes.AlertAlwaysFires, linter.DiagnosticLevelWarning, `hour() + 1`}}},
+		// the selector-free expression may still filter by the time.
+		{`hour() >= 9 < 17`, linter.ExprKindAlertingRule, []reported{}},
+		{`up != 1`, linter.ExprKindAlertingRule, []reported{}},
+		// only the alerting rules are checked.
+		{`vector(1)`, linter.ExprKindQuery, []reported{}},
+		{`sum(rate(x[5m]))`, linter.ExprKindRecordingRule, []reported{}},
+	}
+
+	for _, tt := range tests {
+		p := plugin.NewAlertConditionPlugin(linter.PromQLinterColorModeDisable)
+		assert.Equal(t, tt.expected, pluginDiagnostics(t, tt.expr, &linter.ExprContext{Kind: tt.kind}, p), tt.expr)
+	}
+}
@@ -0,0 +1,229 @@
+/*
+MIT License
+
+# Copyright (c) 2022 Drumato
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package plugin
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Drumato/promqlinter/pkg/codes"
+	"github.com/Drumato/promqlinter/pkg/linter"
+	"github.com/Drumato/promqlinter/pkg/promqlutil"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/promql/parser"
+)
+
+// boundedValueFunctions are the functions that return a few distinct values.
+var boundedValueFunctions = map[string]struct{}{
+	"absent":            {},
+	"absent_over_time":  {},
+	"present_over_time": {},
+	"ceil":              {},
+	"floor":             {},
+	"round":             {},
+	"sgn":               {},
+	"day_of_month":      {},
+	"day_of_week":       {},
+	"days_in_month":     {},
+	"hour":              {},
+	"minute":            {},
+	"month":             {},
+	"year":              {},
+}
+
+// unboundedValueSuffixes are the metric name suffixes of the counters and the measurements.
+var unboundedValueSuffixes = []string{
+	"_total", "_count", "_sum", "_bucket", "_seconds", "_bytes", "_ratio",
+}
+
+type cardinality struct {
+	highCardinalityLabels []string
+	color                 linter.PromQLinterColorMode
+}
+
+// Execute implements linter.PromQLinterPlugin
+func (c *cardinality) Execute(expr parser.Expr) (linter.Diagnostics, error) {
+	return c.ExecuteWithContext(expr, &linter.ExprContext{Kind: linter.ExprKindQuery})
+}
+
+// ExecuteWithContext implements linter.PromQLinterContextPlugin
+func (c *cardinality) ExecuteWithContext(
+	expr parser.Expr,
+	ctx *linter.ExprContext,
+) (linter.Diagnostics, error) {
+	ds := linter.NewDiagnostics()
+	// grouped is the set of the high-cardinality labels that the aggregation-grouping plugin reports.
+	grouped := map[string]struct{}{}
+	parser.Inspect(expr, func(n parser.Node, path []parser.Node) error {
+		switch node := n.(type) {
+		case *parser.AggregateExpr:
+			for _, name := range highCardinalityGrouping(node, c.highCardinalityLabels) {
+				grouped[name] = struct{}{}
+			}
+
+			for _, d := range c.checkAggregation(node) {
+				ds.Add(d)
+			}
+
+			return nil
+		default:
+			// traverse all the non-nil children.
+			return nil
+		}
+	})
+
+	if ctx.Kind == linter.ExprKindRecordingRule {
+		for _, d := range c.checkRecordedLabels(expr, ctx, grouped) {
+			ds.Add(d)
+		}
+	}
+
+	return ds, nil
+}
+
+// checkAggregation reports count_values on unbounded values.
+// the grouping by the high-cardinality labels is reported by the aggregation-grouping plugin.
+func (c *cardinality) checkAggregation(node *parser.AggregateExpr) []linter.Diagnostic {
+	ds := []linter.Diagnostic{}
+
+	if node.Op == parser.COUNT_VALUES && hasUnboundedValues(node.Expr) {
+		msg := fmt.Sprintf(
+			"`count_values` on `%s` creates a series for each distinct value; count bounded values like versions or states only",
+			node.Expr,
+		)
+		ds = append(ds, linter.WarningDiagnostic(node.PosRange, msg, c.color).WithCode(codes.UnboundedCountValues))
+	}
+
+	return ds
+}
+
+// checkRecordedLabels reports the high-cardinality labels that the recording rule keeps.
+// the labels that the series only may have are not reported since every label may be kept without an aggregation,
+// and the labels in grouped are reported by the aggregation-grouping plugin.
+func (c *cardinality) checkRecordedLabels(
+	expr parser.Expr,
+	ctx *linter.ExprContext,
+	grouped map[string]struct{},
+) []linter.Diagnostic {
+	ds := []linter.Diagnostic{}
+	ls := promqlutil.InferLabels(expr)
+
+	for _, name := range c.highCardinalityLabels {
+		if _, ok := grouped[name]; ok || !ls.Has(name) {
+			continue
+		}
+
+		msg := fmt.Sprintf(
+			"the recording rule `%s` keeps the high-cardinality label `%s`",
+			ctx.Name, name,
+		)
+		ds = append(ds, linter.WarningDiagnostic(expr.PositionRange(), msg, c.color).WithCode(codes.HighCardinalityRecording))
+	}
+
+	return ds
+}
+
+// Name implements linter.PromQLinterPlugin
+func (*cardinality) Name() string {
+	return "cardinality"
+}
+
+// NewCardinalityPlugin creates a cardinality plugin.
+func NewCardinalityPlugin(
+	highCardinalityLabels []string,
+	color linter.PromQLinterColorMode,
+) linter.PromQLinterContextPlugin {
+	return &cardinality{highCardinalityLabels, color}
+}
+
+// hasUnboundedValues determines whether the sample values of the expression
+// may take a lot of distinct values like counters, rates and ratios.
+func hasUnboundedValues(expr parser.Expr) bool {
+	switch e := unwrapParenExpr(expr).(type) {
+	case *parser.VectorSelector:
+		name := ""
+		for _, lm := range e.LabelMatchers {
+			if lm.Name == labels.MetricName && lm.Type == labels.MatchEqual {
+				name = lm.Value
+			}
+		}
+
+		for _, suffix := range unboundedValueSuffixes {
+			if strings.HasSuffix(name, suffix) {
+				return true
+			}
+		}
+
+		return false
+	case *parser.Call:
+		if _, ok := boundedValueFunctions[e.Func.Name]; ok {
+			return false
+		}
+
+		for _, arg := range e.Args {
+			if arg.Type() == parser.ValueTypeMatrix {
+				// the functions over ranges like rate() and avg_over_time().
+				return e.Func.Name != "changes" && e.Func.Name != "resets" && e.Func.Name != "count_over_time"
+			}
+		}
+
+		for _, arg := range e.Args {
+			if arg.Type() == parser.ValueTypeVector {
+				return hasUnboundedValues(arg)
+			}
+		}
+
+		return false
+	case *parser.BinaryExpr:
+		if e.ReturnBool || e.Op.IsSetOperator() {
+			return false
+		}
+
+		if e.Op.IsComparisonOperator() {
+			if e.LHS.Type() == parser.ValueTypeVector {
+				return hasUnboundedValues(e.LHS)
+			}
+
+			return hasUnboundedValues(e.RHS)
+		}
+
+		if e.Op == parser.DIV {
+			return true
+		}
+
+		return hasUnboundedValues(e.LHS) || hasUnboundedValues(e.RHS)
+	case *parser.AggregateExpr:
+		switch e.Op {
+		case parser.MIN, parser.MAX, parser.TOPK, parser.BOTTOMK:
+			return hasUnboundedValues(e.Expr)
+		case parser.COUNT, parser.GROUP, parser.COUNT_VALUES:
+			return false
+		default:
+			return true
+		}
+	default:
+		return false
+	}
+}
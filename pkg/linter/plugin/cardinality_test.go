@@ -0,0 +1,120 @@
+/*
+MIT License
+
+# Copyright (c) 2022 Drumato
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package plugin_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/Drumato/promqlinter/pkg/codes"
+	"github.com/Drumato/promqlinter/pkg/linter"
+	"github.com/Drumato/promqlinter/pkg/linter/plugin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCardinality(t *testing.T) {
+	query := &linter.ExprContext{Kind: linter.ExprKindQuery}
+	recording := &linter.ExprContext{Kind: linter.ExprKindRecordingRule, Name: "job:http_requests:rate5m"}
+
+	tests := []struct {
+		expr     string
+		ctx      *linter.ExprContext
+		expected []string
+	}{
+		{`sum by (job) (rate(http_requests_total[5m]))`, query, nil},
+		{`sum without (pod) (rate(http_requests_total[5m]))`, query, nil},
+		{`count_values("version", build_info)`, query, nil},
+		{`count_values("up", up == bool 1)`, query, nil},
+		{`sum by (job, pod) (up)`, query, nil},
+		{`count_values("rate", rate(http_requests_total[5m]))`, query, []string{"[WARN]", "`count_values` on `rate(http_requests_total[5m])` creates a series for each distinct value"}},
+		{`count_values("latency", http_request_duration_seconds)`, query, []string{"[WARN]", "creates a series for each distinct value"}},
+		{`sum by (job) (rate(http_requests_total[5m]))`, recording, nil},
+		{`rate(http_requests_total[5m])`, recording, nil},
+		{`sum by (job, path) (rate(http_requests_total[5m]))`, recording, nil},
+		{`label_replace(sum by (job) (rate(http_requests_total[5m])), "path", "$1", "job", "(.*)")`, recording, []string{"[WARN]", "the recording rule `job:http_requests:rate5m` keeps the high-cardinality label `path`"}},
+	}
+
+	for _, tt := range tests {
+		p := plugin.NewCardinalityPlugin([]string{"pod", "path"}, linter.PromQLinterColorModeDisable)
+		out := pluginTestWithContext(t, tt.expr, tt.ctx, p)
+
+		if tt.expected == nil {
+			assert.Empty(t, out, tt.expr)
+		}
+		for _, s := range tt.expected {
+			assert.Contains(t, out, s, tt.expr)
+		}
+	}
+}
+
+func TestCardinalityRecordingGroupingReportedOnce(t *testing.T) {
+	highCardinalityLabels := []string{"pod", "path"}
+	out := &bytes.Buffer{}
+	l := linter.New(
+		linter.WithPlugins(
+			plugin.NewAggregationGroupingPlugin(plugin.AggregationGroupingConfig{}, highCardinalityLabels, linter.PromQLinterColorModeDisable),
+			plugin.NewCardinalityPlugin(highCardinalityLabels, linter.PromQLinterColorModeDisable),
+		),
+		linter.WithOutStream(out),
+		linter.WithANSIColorMode(linter.PromQLinterColorModeDisable),
+	)
+
+	ctx := &linter.ExprContext{Kind: linter.ExprKindRecordingRule, Name: "job:http_requests:rate5m"}
+	_, err := l.ExecuteWithContext(`sum by (job, path) (rate(http_requests_total[5m]))`, ctx, linter.DiagnosticLevelInfo)
+	assert.NoError(t, err)
+
+	assert.Equal(t, 1, strings.Count(out.String(), "aggregation-grouping<"))
+	assert.NotContains(t, out.String(), codes.HighCardinalityRecording)
+}
+
+func TestCardinalityDiagnostics(t *testing.T) {
+	recording := &linter.ExprContext{Kind: linter.ExprKindRecordingRule, Name: "job:http_requests:rate5m"}
+
+	tests := []struct {
+		expr     string
+		ctx      *linter.ExprContext
+		expected []reported
+	}{
+		{`sum(count_values("rate", rate(x[5m])))`, nil, []reported{{codes.UnboundedCountValues, linter.DiagnosticLevelWarning, `count_values("rate", rate(x[5m]))`}}},
+		{`count_values("up", up == bool 1)`, nil, []reported{}},
+		{`x{path="/"}`, recording, []reported{{codes.HighCardinalityRecording, linter.DiagnosticLevelWarning, `x{path="/"}`}}},
+		{
+			`label_replace(sum by (job) (rate(x[5m])), "path", "$1", "job", "(.*)")`,
+			recording,
+			[]reported{{codes.HighCardinalityRecording, linter.DiagnosticLevelWarning, `label_replace(sum by (job) (rate(x[5m])), "path", "$1", "job", "(.*)")`}},
+		},
+		// the empty replacement removes the label.
+		{`label_replace(sum by (job) (x), "pod", "", "", "")`, recording, []reported{}},
+		// the label that the input may not have is not reported.
+		{`sum without (instance) (x)`, recording, []reported{}},
+		{`x{path="/"}`, nil, []reported{}},
+	}
+
+	for _, tt := range tests {
+		p := plugin.NewCardinalityPlugin([]string{"pod", "path"}, linter.PromQLinterColorModeDisable)
+		assert.Equal(t, tt.expected, pluginDiagnostics(t, tt.expr, tt.ctx, p), tt.expr)
+	}
+}
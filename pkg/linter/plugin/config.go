@@ -0,0 +1,115 @@
+/*
+MIT License
+
+# Copyright (c) 2022 Drumato
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package plugin
+
+import (
+	"time"
+
+	"github.com/Drumato/promqlinter/pkg/linter"
+	"github.com/prometheus/common/model"
+)
+
+// Config is the configuration of the default linter plugins.
+type Config struct {
+	// PrometheusVersion is the version of Prometheus that evaluates the expressions.
+	// the prometheus-compatibility plugin is disabled if it's empty.
+	PrometheusVersion string `json:"prometheusVersion,omitempty"`
+	// EvaluationInterval is the evaluation interval of the rule groups that don't specify it.
+	EvaluationInterval model.Duration `json:"evaluationInterval,omitempty"`
+	// DeniedLabels is the set of the label rules the denied-labels plugin denies.
+	DeniedLabels map[LabelName]LabelValuePattern `json:"deniedLabels,omitempty"`
+	// RangeDuration configures the range-duration plugin.
+	RangeDuration RangeDurationConfig `json:"rangeDuration,omitempty"`
+	// QueryCost configures the query-cost plugin.
+	QueryCost QueryCostConfig `json:"queryCost,omitempty"`
+	// DeniedFunctions configures the denied-functions plugin.
+	DeniedFunctions DeniedFunctionsConfig `json:"deniedFunctions,omitempty"`
+	// AggregationGrouping configures the aggregation-grouping plugin.
+	AggregationGrouping AggregationGroupingConfig `json:"aggregationGrouping,omitempty"`
+	// HighCardinalityLabels is the list of the labels that are known to be high-cardinality.
+	HighCardinalityLabels []string `json:"highCardinalityLabels,omitempty"`
+}
+
+// DefaultConfig returns the configuration that is used if nothing is configured.
+func DefaultConfig() *Config {
+	return &Config{
+		EvaluationInterval: model.Duration(time.Minute),
+		DeniedLabels:       map[LabelName]LabelValuePattern{},
+		RangeDuration: RangeDurationConfig{
+			ScrapeInterval:     model.Duration(time.Minute),
+			MinRateRangeFactor: 4,
+			MaxIrateRange:      model.Duration(5 * time.Minute),
+		},
+		QueryCost: QueryCostConfig{
+			MaxSelectors:                20,
+			MaxTotalRange:               model.Duration(7 * 24 * time.Hour),
+			MaxSubquerySteps:            11000,
+			MaxDepth:                    10,
+			MaxUnanchoredRegexSelectors: 3,
+		},
+		DeniedFunctions: DeniedFunctionsConfig{
+			Functions: []DeniedFunction{
+				{
+					Name:    "absent",
+					Message: "the rule records a series only while the input is missing",
+					In:      []string{linter.ExprKindRecordingRule.String()},
+				},
+			},
+		},
+		HighCardinalityLabels: []string{"pod", "user_id", "path"},
+	}
+}
+
+// Validate checks the configuration so that the invalid fields are reported before the lint process.
+func (c *Config) Validate() error {
+	if err := ValidatePrometheusVersion(c.PrometheusVersion); err != nil {
+		return err
+	}
+
+	if err := c.RangeDuration.Validate(); err != nil {
+		return err
+	}
+
+	if err := c.DeniedFunctions.Validate(); err != nil {
+		return err
+	}
+
+	if err := c.AggregationGrouping.Validate(); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// AddDeniedLabels merges the denied labels that are given in the --denied-labels flag format.
+func (c *Config) AddDeniedLabels(value string) {
+	if c.DeniedLabels == nil {
+		c.DeniedLabels = map[LabelName]LabelValuePattern{}
+	}
+
+	for name, pattern := range splitDeniedLabelsFlag(value) {
+		c.DeniedLabels[name] = pattern
+	}
+}
@@ -0,0 +1,121 @@
+/*
+MIT License
+
+# Copyright (c) 2022 Drumato
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package plugin
+
+import (
+	"fmt"
+
+	"github.com/Drumato/promqlinter/pkg/codes"
+	"github.com/Drumato/promqlinter/pkg/linter"
+	"github.com/Drumato/promqlinter/pkg/promqlutil"
+	"github.com/prometheus/prometheus/promql/parser"
+)
+
+type constantExpr struct {
+	color linter.PromQLinterColorMode
+}
+
+// Execute implements linter.PromQLinterPlugin
+func (c *constantExpr) Execute(expr parser.Expr) (linter.Diagnostics, error) {
+	ds := linter.NewDiagnostics()
+	parser.Inspect(expr, func(n parser.Node, path []parser.Node) error {
+		e, ok := n.(parser.Expr)
+		if !ok {
+			return nil
+		}
+
+		if len(path) != 0 {
+			if parent, ok := path[len(path)-1].(parser.Expr); ok {
+				if _, ok := promqlutil.EvalConstant(parent); ok {
+					// the parent has already been reported.
+					return nil
+				}
+			}
+		}
+
+		if constant, ok := promqlutil.EvalConstant(e); ok {
+			if !isFoldable(e) {
+				return nil
+			}
+
+			if constant.Empty {
+				msg := fmt.Sprintf("`%s` always returns an empty vector", e)
+				ds.Add(linter.WarningDiagnostic(e.PositionRange(), msg, c.color).WithCode(codes.EmptyConstantExpr))
+				return nil
+			}
+
+			msg := fmt.Sprintf("`%s` is constant; use `%s` instead", e, constant)
+			fix := linter.NewReplaceFix(fmt.Sprintf("replace with `%s`", constant), e.PositionRange(), fmt.Sprint(constant))
+			ds.Add(linter.InfoDiagnostic(e.PositionRange(), msg, c.color).WithCode(codes.ConstantExpr).WithSuggestedFix(fix))
+			return nil
+		}
+
+		be, ok := e.(*parser.BinaryExpr)
+		if !ok || !be.Op.IsComparisonOperator() {
+			return nil
+		}
+
+		if be.LHS.Type() == parser.ValueTypeScalar && promqlutil.IsSameExpr(be.LHS, be.RHS) {
+			msg := fmt.Sprintf("`%s` compares the scalar with itself", be)
+			ds.Add(linter.WarningDiagnostic(be.PositionRange(), msg, c.color).WithCode(codes.SelfComparison))
+		}
+
+		return nil
+	})
+
+	return ds, nil
+}
+
+// Name implements linter.PromQLinterPlugin
+func (*constantExpr) Name() string {
+	return "constant-expressions"
+}
+
+// NewConstantExprPlugin creates a constant-expressions plugin.
+func NewConstantExprPlugin(
+	color linter.PromQLinterColorMode,
+) linter.PromQLinterPlugin {
+	return &constantExpr{color}
+}
+
+// isFoldable determines whether the constant expression can be written more simply.
+// e.g., `2 * 60` is foldable but `-1` and `vector(1)` are not.
+func isFoldable(expr parser.Expr) bool {
+	foldable := false
+	parser.Inspect(expr, func(n parser.Node, path []parser.Node) error {
+		switch node := n.(type) {
+		case *parser.BinaryExpr:
+			foldable = true
+		case *parser.Call:
+			if node.Func.Name != "vector" {
+				foldable = true
+			}
+		}
+
+		return nil
+	})
+
+	return foldable
+}
@@ -0,0 +1,88 @@
+/*
+MIT License
+
+# Copyright (c) 2022 Drumato
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package plugin_test
+
+import (
+	"testing"
+
+	"github.com/Drumato/promqlinter/pkg/codes"
+	"github.com/Drumato/promqlinter/pkg/linter"
+	"github.com/Drumato/promqlinter/pkg/linter/plugin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConstantExpr(t *testing.T) {
+	tests := []struct {
+		expr     string
+		expected []string
+	}{
+		{`rate(x[5m]) > 0.5`, nil},
+		{`vector(1)`, nil},
+		{`-1`, nil},
+		{`rate(x[5m]) * (2 * 60)`, []string{"[INFO]", "`(2 * 60)` is constant; use `120` instead"}},
+		{`time() - time()`, []string{"[INFO]", "use `0` instead"}},
+		{`vector(1) > 0`, []string{"[INFO]", "use `vector(1)` instead"}},
+		{`vector(1) < 0`, []string{"[WARN]", "`vector(1) < 0` always returns an empty vector"}},
+		{`scalar(up) >= bool scalar(up)`, []string{"[WARN]", "compares the scalar with itself"}},
+	}
+
+	for _, tt := range tests {
+		p := plugin.NewConstantExprPlugin(linter.PromQLinterColorModeDisable)
+		out := pluginTest(t, tt.expr, p)
+
+		if tt.expected == nil {
+			assert.Empty(t, out, tt.expr)
+		}
+		for _, s := range tt.expected {
+			assert.Contains(t, out, s, tt.expr)
+		}
+	}
+
+	out := pluginTest(t, `rate(x[5m]) * (2 * 60)`, plugin.NewConstantExprPlugin(linter.PromQLinterColorModeDisable))
+	assert.NotContains(t, out, "`2 * 60`")
+}
+
+func TestConstantExprDiagnostics(t *testing.T) {
+	tests := []struct {
+		expr     string
+		expected []reported
+	}{
+		// only the outermost constant sub expression is pointed.
+		{`x / (60 * 60)`, []reported{{codes.ConstantExpr, linter.DiagnosticLevelInfo, `(60 * 60)`}}},
+		{`time() - time()`, []reported{{codes.ConstantExpr, linter.DiagnosticLevelInfo, `time() - time()`}}},
+		{`vector(1) > 0`, []reported{{codes.ConstantExpr, linter.DiagnosticLevelInfo, `vector(1) > 0`}}},
+		{`vector(1) < 0`, []reported{{codes.EmptyConstantExpr, linter.DiagnosticLevelWarning, `vector(1) < 0`}}},
+		{`scalar(up) >= bool scalar(up)`, []reported{{codes.SelfComparison, linter.DiagnosticLevelWarning, `scalar(up) >= bool scalar(up)`}}},
+		// `(rate(x[5m]) * 2) * 60` has no constant sub expression.
+		{`rate(x[5m]) * 2 * 60`, []reported{}},
+		{`time() - 3600`, []reported{}},
+		{`scalar(up) - scalar(down)`, []reported{}},
+	}
+
+	for _, tt := range tests {
+		p := plugin.NewConstantExprPlugin(linter.PromQLinterColorModeDisable)
+		assert.Equal(t, tt.expected, pluginDiagnostics(t, tt.expr, nil, p), tt.expr)
+	}
+}
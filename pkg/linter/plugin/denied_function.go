@@ -0,0 +1,203 @@
+/*
+MIT License
+
+# Copyright (c) 2022 Drumato
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package plugin
+
+import (
+	"fmt"
+
+	"github.com/Drumato/promqlinter/pkg/codes"
+	"github.com/Drumato/promqlinter/pkg/linter"
+	"github.com/prometheus/prometheus/promql/parser"
+)
+
+// DeniedFunctionsConfig is the policy of the denied-functions plugin.
+type DeniedFunctionsConfig struct {
+	// Functions is the list of the denied functions.
+	Functions []DeniedFunction `json:"functions,omitempty"`
+	// DenyAtModifier determines whether the `@` modifier is denied.
+	DenyAtModifier bool `json:"denyAtModifier,omitempty"`
+	// DenyOffset determines whether the `offset` modifier is denied.
+	DenyOffset bool `json:"denyOffset,omitempty"`
+}
+
+// DeniedFunction is a function that must not be called.
+type DeniedFunction struct {
+	// Name is the name of the function.
+	Name string `json:"name"`
+	// Message describes why the function is denied.
+	Message string `json:"message,omitempty"`
+	// Replacement is the function that should be used instead.
+	Replacement string `json:"replacement,omitempty"`
+	// In limits the expression kinds (query/alerting/recording) where the function is denied.
+	// the function is denied everywhere if it's empty.
+	In []string `json:"in,omitempty"`
+}
+
+// Validate checks the expression kinds in `In` are known.
+func (c DeniedFunctionsConfig) Validate() error {
+	kinds := map[string]struct{}{
+		linter.ExprKindQuery.String():         {},
+		linter.ExprKindAlertingRule.String():  {},
+		linter.ExprKindRecordingRule.String(): {},
+	}
+
+	for _, f := range c.Functions {
+		for _, in := range f.In {
+			if _, ok := kinds[in]; !ok {
+				return fmt.Errorf(
+					"the expression kind `%s` of the denied function `%s` must be query, alerting or recording",
+					in, f.Name,
+				)
+			}
+		}
+	}
+
+	return nil
+}
+
+// deniedIn determines whether the function is denied in the given kind of the expression.
+func (f *DeniedFunction) deniedIn(kind linter.ExprKind) bool {
+	if len(f.In) == 0 {
+		return true
+	}
+
+	for _, in := range f.In {
+		if in == kind.String() {
+			return true
+		}
+	}
+
+	return false
+}
+
+type deniedFunction struct {
+	config DeniedFunctionsConfig
+	color  linter.PromQLinterColorMode
+}
+
+// Execute implements linter.PromQLinterPlugin
+func (d *deniedFunction) Execute(expr parser.Expr) (linter.Diagnostics, error) {
+	return d.ExecuteWithContext(expr, &linter.ExprContext{Kind: linter.ExprKindQuery})
+}
+
+// ExecuteWithContext implements linter.PromQLinterContextPlugin
+func (d *deniedFunction) ExecuteWithContext(
+	expr parser.Expr,
+	ctx *linter.ExprContext,
+) (linter.Diagnostics, error) {
+	functions := map[string]*DeniedFunction{}
+	for i, f := range d.config.Functions {
+		if f.deniedIn(ctx.Kind) {
+			functions[f.Name] = &d.config.Functions[i]
+		}
+	}
+
+	ds := linter.NewDiagnostics()
+	parser.Inspect(expr, func(n parser.Node, path []parser.Node) error {
+		switch node := n.(type) {
+		case *parser.Call:
+			f, ok := functions[node.Func.Name]
+			if !ok {
+				return nil
+			}
+
+			msg := fmt.Sprintf("`%s` is denied", f.Name)
+			if len(f.In) != 0 {
+				msg = fmt.Sprintf("%s in %s expressions", msg, ctx.Kind)
+			}
+			if f.Message != "" {
+				msg = fmt.Sprintf("%s: %s", msg, f.Message)
+			}
+			if f.Replacement != "" {
+				msg = fmt.Sprintf("%s; use `%s` instead", msg, f.Replacement)
+			}
+
+			ds.Add(linter.WarningDiagnostic(node.PosRange, msg, d.color).WithCode(codes.DeniedFunction))
+			return nil
+		case *parser.VectorSelector:
+			for _, diag := range d.checkModifiers(selectorPosition(node, path), node.Timestamp, node.StartOrEnd, node.OriginalOffset != 0) {
+				ds.Add(diag)
+			}
+
+			return nil
+		case *parser.SubqueryExpr:
+			for _, diag := range d.checkModifiers(node.PositionRange(), node.Timestamp, node.StartOrEnd, node.OriginalOffset != 0) {
+				ds.Add(diag)
+			}
+
+			return nil
+		default:
+			// traverse all the non-nil children.
+			return nil
+		}
+	})
+
+	return ds, nil
+}
+
+// checkModifiers reports the denied modifiers of a selector/subquery.
+func (d *deniedFunction) checkModifiers(
+	pos parser.PositionRange,
+	timestamp *int64,
+	startOrEnd parser.ItemType,
+	hasOffset bool,
+) []linter.Diagnostic {
+	ds := []linter.Diagnostic{}
+
+	if d.config.DenyAtModifier && (timestamp != nil || startOrEnd != 0) {
+		ds = append(ds, linter.ErrorDiagnostic(pos, "the `@` modifier is denied", d.color).WithCode(codes.DeniedAtModifier))
+	}
+
+	if d.config.DenyOffset && hasOffset {
+		ds = append(ds, linter.ErrorDiagnostic(pos, "the `offset` modifier is denied", d.color).WithCode(codes.DeniedOffset))
+	}
+
+	return ds
+}
+
+// selectorPosition returns the position of the selector with its modifiers.
+// the modifiers of a range vector follow the range, so the whole range vector is returned.
+func selectorPosition(node *parser.VectorSelector, path []parser.Node) parser.PositionRange {
+	if len(path) != 0 {
+		if ms, ok := path[len(path)-1].(*parser.MatrixSelector); ok {
+			return ms.PositionRange()
+		}
+	}
+
+	return node.PosRange
+}
+
+// Name implements linter.PromQLinterPlugin
+func (*deniedFunction) Name() string {
+	return "denied-functions"
+}
+
+// NewDeniedFunctionPlugin creates a denied-functions plugin.
+func NewDeniedFunctionPlugin(
+	config DeniedFunctionsConfig,
+	color linter.PromQLinterColorMode,
+) linter.PromQLinterContextPlugin {
+	return &deniedFunction{config, color}
+}
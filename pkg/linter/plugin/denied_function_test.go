@@ -0,0 +1,124 @@
+/*
+MIT License
+
+# Copyright (c) 2022 Drumato
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package plugin_test
+
+import (
+	"testing"
+
+	"github.com/Drumato/promqlinter/pkg/codes"
+	"github.com/Drumato/promqlinter/pkg/linter"
+	"github.com/Drumato/promqlinter/pkg/linter/plugin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDeniedFunctions(t *testing.T) {
+	config := plugin.DefaultConfig().DeniedFunctions
+	config.DenyAtModifier = true
+	config.DenyOffset = true
+	config.Functions = append(config.Functions, plugin.DeniedFunction{
+		Name:        "irate",
+		Message:     "it is too sensitive to a single sample",
+		Replacement: "rate",
+		In:          []string{"alerting"},
+	})
+
+	tests := []struct {
+		expr     string
+		kind     linter.ExprKind
+		expected []string
+	}{
+		{`rate(http_requests_total[5m])`, linter.ExprKindQuery, nil},
+		{`absent(up{job="node"})`, linter.ExprKindAlertingRule, nil},
+		{`absent(up{job="node"})`, linter.ExprKindRecordingRule, []string{"[WARN]", "`absent` is denied in recording expressions"}},
+		{`holt_winters(x[1h], 0.5, 0.5)`, linter.ExprKindQuery, nil},
+		{`irate(x[5m])`, linter.ExprKindQuery, nil},
+		{`irate(x[5m]) > 1`, linter.ExprKindAlertingRule, []string{"[WARN]", "`irate` is denied in alerting expressions: it is too sensitive to a single sample; use `rate` instead"}},
+		{`up @ 1609746000`, linter.ExprKindQuery, []string{"[ERROR]", "the `@` modifier is denied"}},
+		{`rate(x[5m] offset 1h)`, linter.ExprKindQuery, []string{"[ERROR]", "the `offset` modifier is denied"}},
+		{`max_over_time(rate(x[5m])[1h:] @ end())`, linter.ExprKindQuery, []string{"[ERROR]", "the `@` modifier is denied"}},
+	}
+
+	for _, tt := range tests {
+		p := plugin.NewDeniedFunctionPlugin(config, linter.PromQLinterColorModeDisable)
+		out := pluginTestWithContext(t, tt.expr, &linter.ExprContext{Kind: tt.kind}, p)
+
+		if tt.expected == nil {
+			assert.Empty(t, out, tt.expr)
+		}
+		for _, s := range tt.expected {
+			assert.Contains(t, out, s, tt.expr)
+		}
+	}
+}
+
+func TestDeniedFunctionsDiagnostics(t *testing.T) {
+	config := plugin.DefaultConfig().DeniedFunctions
+	config.DenyAtModifier = true
+	config.DenyOffset = true
+	config.Functions = append(config.Functions, plugin.DeniedFunction{Name: "irate", In: []string{"alerting"}})
+
+	tests := []struct {
+		expr     string
+		kind     linter.ExprKind
+		expected []reported
+	}{
+		{`sum(absent(up{job="node"}))`, linter.ExprKindRecordingRule, []reported{{codes.DeniedFunction, linter.DiagnosticLevelWarning, `absent(up{job="node"})`}}},
+		{`irate(x[5m]) > 1`, linter.ExprKindAlertingRule, []reported{{codes.DeniedFunction, linter.DiagnosticLevelWarning, `irate(x[5m])`}}},
+		// the function is denied only in the given kinds, and only the exact name is denied.
+		{`absent(up)`, linter.ExprKindAlertingRule, []reported{}},
+		{`absent_over_time(up[5m])`, linter.ExprKindRecordingRule, []reported{}},
+		{`up @ 1609746000`, linter.ExprKindQuery, []reported{{codes.DeniedAtModifier, linter.DiagnosticLevelError, `up @ 1609746000`}}},
+		// the modifiers of a range vector point the whole range vector.
+		{`sum(rate(x{job="a"}[5m] offset 1h))`, linter.ExprKindQuery, []reported{{codes.DeniedOffset, linter.DiagnosticLevelError, `x{job="a"}[5m] offset 1h`}}},
+		{
+			`rate(x[5m] @ 100 offset 1h)`,
+			linter.ExprKindQuery,
+			[]reported{
+				{codes.DeniedAtModifier, linter.DiagnosticLevelError, `x[5m] @ 100 offset 1h`},
+				{codes.DeniedOffset, linter.DiagnosticLevelError, `x[5m] @ 100 offset 1h`},
+			},
+		},
+		{`max_over_time(rate(x[5m])[1h:] offset 5m)`, linter.ExprKindQuery, []reported{{codes.DeniedOffset, linter.DiagnosticLevelError, `rate(x[5m])[1h:] offset 5m`}}},
+	}
+
+	for _, tt := range tests {
+		p := plugin.NewDeniedFunctionPlugin(config, linter.PromQLinterColorModeDisable)
+		assert.Equal(t, tt.expected, pluginDiagnostics(t, tt.expr, &linter.ExprContext{Kind: tt.kind}, p), tt.expr)
+	}
+}
+
+func TestDeniedFunctionsValidate(t *testing.T) {
+	config := plugin.DeniedFunctionsConfig{
+		Functions: []plugin.DeniedFunction{
+			{Name: "irate", In: []string{"alerting", "recording", "query"}},
+		},
+	}
+	assert.NoError(t, config.Validate())
+
+	// the kinds are compared with the exact names, so a typo would deny nothing.
+	config.Functions = append(config.Functions, plugin.DeniedFunction{Name: "absent", In: []string{"record"}})
+	assert.Error(t, config.Validate())
+	assert.Error(t, (&plugin.Config{DeniedFunctions: config}).Validate())
+}
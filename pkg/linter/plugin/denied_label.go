@@ -29,6 +29,7 @@ import (
 	"regexp"
 	"strings"
 
+	"github.com/Drumato/promqlinter/pkg/codes"
 	"github.com/Drumato/promqlinter/pkg/linter"
 	"github.com/prometheus/prometheus/promql/parser"
 )
@@ -73,11 +74,12 @@ func (d *deniedLabel) Execute(expr parser.Expr) (linter.Diagnostics, error) {
 
 				if exp.MatchString(lm.Value) {
 					msg := fmt.Sprintf("matched to the denied label rule `%s`", pattern)
+					fix := replaceMatcherFix(fmt.Sprintf("remove `%s`", lm), node, path, lm, nil)
 					ds.Add(linter.ErrorDiagnostic(
 						node.PosRange,
 						msg,
 						d.color,
-					))
+					).WithCode(codes.DeniedLabel).WithSuggestedFix(fix))
 				}
 			}
 
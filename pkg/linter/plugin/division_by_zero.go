@@ -0,0 +1,227 @@
+/*
+MIT License
+
+# Copyright (c) 2022 Drumato
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package plugin
+
+import (
+	"fmt"
+
+	"github.com/Drumato/promqlinter/pkg/codes"
+	"github.com/Drumato/promqlinter/pkg/linter"
+	"github.com/Drumato/promqlinter/pkg/promqlutil"
+	"github.com/prometheus/prometheus/promql/parser"
+)
+
+type divisionByZero struct {
+	color linter.PromQLinterColorMode
+}
+
+// Execute implements linter.PromQLinterPlugin
+func (d *divisionByZero) Execute(expr parser.Expr) (linter.Diagnostics, error) {
+	ds := linter.NewDiagnostics()
+	parser.Inspect(expr, func(n parser.Node, path []parser.Node) error {
+		switch node := n.(type) {
+		case *parser.BinaryExpr:
+			if node.Op != parser.DIV && node.Op != parser.MOD {
+				return nil
+			}
+
+			if node.LHS.Type() != parser.ValueTypeVector || node.RHS.Type() != parser.ValueTypeVector {
+				return nil
+			}
+
+			if isGuardedDenominator(node.RHS) {
+				return nil
+			}
+
+			msg := fmt.Sprintf("the denominator `%s` may be zero, which yields NaN/Inf", node.RHS)
+			var fix *linter.SuggestedFix
+			if guard, ok := zeroGuard(node.RHS); ok {
+				guarded := &parser.BinaryExpr{
+					Op:             node.Op,
+					LHS:            node.LHS,
+					RHS:            &parser.ParenExpr{Expr: guard},
+					VectorMatching: node.VectorMatching,
+					ReturnBool:     node.ReturnBool,
+				}
+				msg = fmt.Sprintf("%s; guard it like `%s`", msg, guarded)
+				fix = linter.NewReplaceFix("guard the denominator with `> 0`", node.PositionRange(), guarded.String())
+			}
+			ds.Add(linter.WarningDiagnostic(node.PositionRange(), msg, d.color).WithCode(codes.DivisionByZero).WithSuggestedFix(fix))
+
+			return nil
+		default:
+			// traverse all the non-nil children.
+			return nil
+		}
+	})
+
+	return ds, nil
+}
+
+// Name implements linter.PromQLinterPlugin
+func (*divisionByZero) Name() string {
+	return "division-by-zero"
+}
+
+// NewDivisionByZeroPlugin creates a division-by-zero plugin.
+func NewDivisionByZeroPlugin(
+	color linter.PromQLinterColorMode,
+) linter.PromQLinterPlugin {
+	return &divisionByZero{color}
+}
+
+// isGuardedDenominator determines whether the expression never returns zero.
+func isGuardedDenominator(expr parser.Expr) bool {
+	if c, ok := promqlutil.EvalConstant(expr); ok {
+		return c.Value != 0
+	}
+
+	switch e := unwrapParenExpr(expr).(type) {
+	case *parser.BinaryExpr:
+		switch {
+		case e.Op == parser.LOR:
+			// the fallback of the missing denominator must not be zero as well.
+			return isGuardedDenominator(e.LHS) && isGuardedDenominator(e.RHS)
+		case e.Op == parser.LAND:
+			return isGuardedDenominator(e.LHS) || isGuardedDenominator(e.RHS)
+		case e.Op.IsComparisonOperator() && !e.ReturnBool:
+			return excludesZero(e)
+		default:
+			return false
+		}
+	case *parser.Call:
+		switch e.Func.Name {
+		case "clamp_min", "clamp":
+			c, ok := promqlutil.EvalConstant(e.Args[1])
+			return ok && c.Value > 0
+		default:
+			return false
+		}
+	case *parser.AggregateExpr:
+		switch e.Op {
+		case parser.COUNT:
+			return true
+		case parser.MIN, parser.MAX:
+			// the result is one of the samples.
+			return isGuardedDenominator(e.Expr)
+		case parser.SUM, parser.AVG:
+			// the nonzero samples like `x != 0` may still sum up to zero, but the positive ones don't.
+			return isPositiveFilter(e.Expr)
+		default:
+			return false
+		}
+	default:
+		return false
+	}
+}
+
+// zeroGuard returns the denominator that drops the zero samples.
+// it returns false if the denominator is already filtered by a comparison,
+// since replacing the bound changes the meaning and stacking `> 0` on it is redundant.
+func zeroGuard(expr parser.Expr) (parser.Expr, bool) {
+	operand := unwrapParenExpr(expr)
+	if be, ok := operand.(*parser.BinaryExpr); ok && be.Op.IsComparisonOperator() && !be.ReturnBool {
+		return nil, false
+	}
+
+	return &parser.BinaryExpr{
+		Op:  parser.GTR,
+		LHS: operand,
+		RHS: &parser.NumberLiteral{Val: 0},
+	}, true
+}
+
+// isPositiveFilter determines whether the expression is a filter that keeps the positive samples only like `x > 0`.
+func isPositiveFilter(expr parser.Expr) bool {
+	e, ok := unwrapParenExpr(expr).(*parser.BinaryExpr)
+	if !ok || !e.Op.IsComparisonOperator() || e.ReturnBool {
+		return false
+	}
+
+	op, c, ok := normalizeConstantComparison(e)
+	if !ok {
+		return false
+	}
+
+	switch op {
+	case parser.GTR:
+		return c >= 0
+	case parser.GTE, parser.EQLC:
+		return c > 0
+	default:
+		return false
+	}
+}
+
+// normalizeConstantComparison returns the operator and the constant of the comparison
+// as if the constant is on the right-hand side, e.g., `0 < x` is regarded as `x > 0`.
+func normalizeConstantComparison(e *parser.BinaryExpr) (parser.ItemType, float64, bool) {
+	if c, ok := promqlutil.EvalConstant(e.RHS); ok {
+		return e.Op, c.Value, true
+	}
+
+	c, ok := promqlutil.EvalConstant(e.LHS)
+	if !ok {
+		return e.Op, 0, false
+	}
+
+	switch e.Op {
+	case parser.GTR:
+		return parser.LSS, c.Value, true
+	case parser.LSS:
+		return parser.GTR, c.Value, true
+	case parser.GTE:
+		return parser.LTE, c.Value, true
+	case parser.LTE:
+		return parser.GTE, c.Value, true
+	default:
+		return e.Op, c.Value, true
+	}
+}
+
+// excludesZero determines whether the filter comparison drops the zero samples.
+func excludesZero(e *parser.BinaryExpr) bool {
+	op, c, ok := normalizeConstantComparison(e)
+	if !ok {
+		return false
+	}
+
+	switch op {
+	case parser.GTR:
+		return c >= 0
+	case parser.GTE:
+		return c > 0
+	case parser.LSS:
+		return c <= 0
+	case parser.LTE:
+		return c < 0
+	case parser.NEQ:
+		return c == 0
+	case parser.EQLC:
+		return c != 0
+	default:
+		return false
+	}
+}
@@ -0,0 +1,101 @@
+/*
+MIT License
+
+# Copyright (c) 2022 Drumato
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package plugin_test
+
+import (
+	"testing"
+
+	"github.com/Drumato/promqlinter/pkg/codes"
+	"github.com/Drumato/promqlinter/pkg/linter"
+	"github.com/Drumato/promqlinter/pkg/linter/plugin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDivisionByZero(t *testing.T) {
+	tests := []struct {
+		expr     string
+		expected []string
+	}{
+		{`rate(errors[5m]) / 60`, nil},
+		{`rate(errors[5m]) / (rate(requests[5m]) > 0)`, nil},
+		{`rate(errors[5m]) / (0 < rate(requests[5m]))`, nil},
+		{`rate(errors[5m]) / clamp_min(rate(requests[5m]), 1)`, nil},
+		{`rate(errors[5m]) / (rate(requests[5m]) > 0 or vector(1))`, nil},
+		{`rate(errors[5m]) / (rate(requests[5m]) or vector(1))`, []string{"the denominator `(rate(requests[5m]) or vector(1))` may be zero"}},
+		{`x / (y > 0 or vector(0))`, []string{"the denominator `(y > 0 or vector(0))` may be zero"}},
+		{`sum(rate(errors[5m])) / sum(rate(requests[5m]) > 0)`, nil},
+		{`sum(up) / count(up)`, nil},
+		{`rate(errors[5m]) / rate(requests[5m])`, []string{"[WARN]", "the denominator `rate(requests[5m])` may be zero", "guard it like `rate(errors[5m]) / (rate(requests[5m]) > 0)`"}},
+		{`a / on (job) (b >= 0)`, []string{"the denominator `(b >= 0)` may be zero, which yields NaN/Inf\n"}},
+		{`a / (-1 < b)`, []string{"the denominator `(-1 < b)` may be zero, which yields NaN/Inf\n"}},
+		{`a / min(b != 0)`, nil},
+		{`a / max(0 < b)`, nil},
+		{`a / avg(b >= 1)`, nil},
+		{`a / sum(b != 0)`, []string{"the denominator `sum(b != 0)` may be zero"}},
+		{`a / avg(b != 0)`, []string{"the denominator `avg(b != 0)` may be zero"}},
+		{`a / sum(b > -1)`, []string{"the denominator `sum(b > -1)` may be zero"}},
+		{`a / clamp_min(b, 0)`, []string{"the denominator `clamp_min(b, 0)` may be zero"}},
+		{`a / (b < 10)`, []string{"the denominator `(b < 10)` may be zero, which yields NaN/Inf\n"}},
+	}
+
+	for _, tt := range tests {
+		p := plugin.NewDivisionByZeroPlugin(linter.PromQLinterColorModeDisable)
+		out := pluginTest(t, tt.expr, p)
+
+		if tt.expected == nil {
+			assert.Empty(t, out, tt.expr)
+		}
+		for _, s := range tt.expected {
+			assert.Contains(t, out, s, tt.expr)
+		}
+	}
+}
+
+func TestDivisionByZeroDiagnostics(t *testing.T) {
+	tests := []struct {
+		expr     string
+		expected []reported
+	}{
+		// the whole division is pointed so the fix can replace it.
+		{`sum(a) / sum(b) * 100`, []reported{{codes.DivisionByZero, linter.DiagnosticLevelWarning, `sum(a) / sum(b)`}}},
+		{`a % b`, []reported{{codes.DivisionByZero, linter.DiagnosticLevelWarning, `a % b`}}},
+		{`a / on (job) b`, []reported{{codes.DivisionByZero, linter.DiagnosticLevelWarning, `a / on (job) b`}}},
+		// the filter must exclude zero.
+		{`a / (b >= 0)`, []reported{{codes.DivisionByZero, linter.DiagnosticLevelWarning, `a / (b >= 0)`}}},
+		{`a / (b > -0.5)`, []reported{{codes.DivisionByZero, linter.DiagnosticLevelWarning, `a / (b > -0.5)`}}},
+		{`a / (b == 0)`, []reported{{codes.DivisionByZero, linter.DiagnosticLevelWarning, `a / (b == 0)`}}},
+		{`a / (b > 0)`, []reported{}},
+		{`a / (b >= 0.5)`, []reported{}},
+		{`a / (b == 1)`, []reported{}},
+		{`a / count(b)`, []reported{}},
+		// only the divisions of two vectors are checked.
+		{`a / 0`, []reported{}},
+	}
+
+	for _, tt := range tests {
+		p := plugin.NewDivisionByZeroPlugin(linter.PromQLinterColorModeDisable)
+		assert.Equal(t, tt.expected, pluginDiagnostics(t, tt.expr, nil, p), tt.expr)
+	}
+}
@@ -0,0 +1,176 @@
+/*
+MIT License
+
+# Copyright (c) 2022 Drumato
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package plugin
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/Drumato/promqlinter/pkg/codes"
+	"github.com/Drumato/promqlinter/pkg/linter"
+	"github.com/Drumato/promqlinter/pkg/manifest"
+	"github.com/Drumato/promqlinter/pkg/rulegraph"
+	"github.com/prometheus/prometheus/promql/parser"
+)
+
+type duplicateRule struct {
+	graph *rulegraph.Graph
+	// exprs holds the normalized expressions of the rules.
+	exprs map[*manifest.Rule]string
+	color linter.PromQLinterColorMode
+}
+
+// Execute implements linter.PromQLinterPlugin
+func (d *duplicateRule) Execute(expr parser.Expr) (linter.Diagnostics, error) {
+	return d.ExecuteWithContext(expr, &linter.ExprContext{Kind: linter.ExprKindQuery})
+}
+
+// ExecuteWithContext implements linter.PromQLinterContextPlugin
+func (d *duplicateRule) ExecuteWithContext(
+	expr parser.Expr,
+	ctx *linter.ExprContext,
+) (linter.Diagnostics, error) {
+	ds := linter.NewDiagnostics()
+
+	current, ok := d.graph.RuleOf(ctx)
+	if !ok {
+		// the expression doesn't come from the scanned manifests.
+		return ds, nil
+	}
+
+	sameName := []*manifest.Rule{}
+	sameExpr := []*manifest.Rule{}
+	for _, r := range d.graph.Rules {
+		if r == current {
+			continue
+		}
+
+		if r.Kind() == current.Kind() && r.Name() == current.Name() {
+			if current.Alert != "" && labelsEqual(r.Labels, current.Labels) {
+				sameName = append(sameName, r)
+			}
+			if current.Record != "" && labelsOverlap(r.Labels, current.Labels) {
+				sameName = append(sameName, r)
+			}
+		}
+
+		// the rules with the same name are the variants like the alerts with the other severities,
+		// or they are reported as the duplicates above.
+		if e, ok := d.exprs[r]; ok && e == d.exprs[current] && r.Name() != current.Name() {
+			sameExpr = append(sameExpr, r)
+		}
+	}
+
+	if len(sameName) != 0 {
+		if current.Alert != "" {
+			msg := fmt.Sprintf(
+				"the alert `%s` with the same labels is also defined at %s",
+				current.Alert, locations(sameName),
+			)
+			ds.Add(linter.WarningDiagnostic(expr.PositionRange(), msg, d.color).WithCode(codes.DuplicateAlert))
+		} else {
+			msg := fmt.Sprintf(
+				"the recording rule `%s` with overlapping labels is also defined at %s; the rules record the same series",
+				current.Record, locations(sameName),
+			)
+			ds.Add(linter.ErrorDiagnostic(expr.PositionRange(), msg, d.color).WithCode(codes.DuplicateRecordingRule))
+		}
+	}
+
+	if len(sameExpr) != 0 {
+		msg := fmt.Sprintf("the same expression is also used at %s", locations(sameExpr))
+		ds.Add(linter.WarningDiagnostic(expr.PositionRange(), msg, d.color).WithCode(codes.DuplicateExpression))
+	}
+
+	return ds, nil
+}
+
+// Name implements linter.PromQLinterPlugin
+func (*duplicateRule) Name() string {
+	return "duplicate-rules"
+}
+
+// NewDuplicateRulePlugin creates a duplicate-rules plugin.
+// graph has all the rules in the scanned manifests.
+func NewDuplicateRulePlugin(
+	graph *rulegraph.Graph,
+	color linter.PromQLinterColorMode,
+) linter.PromQLinterContextPlugin {
+	d := &duplicateRule{
+		graph: graph,
+		exprs: map[*manifest.Rule]string{},
+		color: color,
+	}
+
+	for _, r := range graph.Rules {
+		// the expressions are compared in the formatted form without the enclosing parentheses,
+		// so the spaces and the outermost parentheses are ignored but the inner parentheses are not.
+		if expr, err := parser.ParseExpr(r.Expr.StrVal); err == nil {
+			d.exprs[r] = unwrapParenExpr(expr).String()
+		}
+	}
+
+	return d
+}
+
+// labelsEqual determines whether the given static labels are the same.
+func labelsEqual(lhs, rhs map[string]string) bool {
+	if len(lhs) != len(rhs) {
+		return false
+	}
+
+	return labelsOverlap(lhs, rhs)
+}
+
+// labelsOverlap determines whether the given static labels may produce the same series,
+// that is, the common label names have the same values.
+func labelsOverlap(lhs, rhs map[string]string) bool {
+	for name, value := range lhs {
+		if v, ok := rhs[name]; ok && v != value {
+			return false
+		}
+	}
+
+	return true
+}
+
+// locations returns the sorted locations of the rules like `a.yaml:10, b.yaml:20`.
+func locations(rules []*manifest.Rule) string {
+	sorted := append([]*manifest.Rule{}, rules...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].File != sorted[j].File {
+			return sorted[i].File < sorted[j].File
+		}
+		return sorted[i].Line < sorted[j].Line
+	})
+
+	locs := make([]string, 0, len(sorted))
+	for _, r := range sorted {
+		locs = append(locs, r.Location())
+	}
+
+	return strings.Join(locs, ", ")
+}
@@ -0,0 +1,140 @@
+/*
+MIT License
+
+# Copyright (c) 2022 Drumato
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package plugin_test
+
+import (
+	"testing"
+
+	"github.com/Drumato/promqlinter/pkg/codes"
+	"github.com/Drumato/promqlinter/pkg/linter"
+	"github.com/Drumato/promqlinter/pkg/linter/plugin"
+	"github.com/Drumato/promqlinter/pkg/manifest"
+	"github.com/Drumato/promqlinter/pkg/rulegraph"
+	"github.com/stretchr/testify/assert"
+)
+
+const duplicateRulesManifestA = `spec:
+  groups:
+  - name: a
+    rules:
+    - alert: HighErrors
+      expr: sum(rate(errors_total[5m])) > 1
+      labels:
+        severity: critical
+    - record: job:up:sum
+      expr: sum by (job) (up)
+      labels:
+        env: prod
+    - alert: Unique
+      expr: up == 0
+`
+
+const duplicateRulesManifestB = `spec:
+  groups:
+  - name: b
+    rules:
+    - alert: HighErrors
+      expr: (sum(rate(errors_total[5m])) > 1)
+      labels:
+        severity: critical
+    - alert: HighErrors
+      expr: sum(rate(errors_total[5m])) > 10
+      labels:
+        severity: warning
+    - record: job:up:sum
+      expr: count by (job) (up)
+    - record: job:up:sum
+      expr: max by (job) (up)
+      labels:
+        env: dev
+    - alert: Down
+      expr: (up  ==  0)
+`
+
+func TestDuplicateRules(t *testing.T) {
+	a, err := manifest.Parse("a.yaml", []byte(duplicateRulesManifestA))
+	assert.NoError(t, err)
+	b, err := manifest.Parse("b.yaml", []byte(duplicateRulesManifestB))
+	assert.NoError(t, err)
+	rules := append(a.Rules, b.Rules...)
+
+	tests := []struct {
+		rule     *manifest.Rule
+		expected []string
+	}{
+		{a.Rules[0], []string{"[WARN]", "the alert `HighErrors` with the same labels is also defined at b.yaml:5"}},
+		{a.Rules[1], []string{"[ERROR]", "the recording rule `job:up:sum` with overlapping labels is also defined at b.yaml:13"}},
+		{a.Rules[2], []string{"[WARN]", "PQL1803 (1:1) the same expression is also used at b.yaml:19"}},
+		{b.Rules[1], nil},
+		{b.Rules[2], []string{"[ERROR]", "is also defined at a.yaml:9, b.yaml:15"}},
+	}
+
+	for _, tt := range tests {
+		p := plugin.NewDuplicateRulePlugin(rulegraph.New(rules), linter.PromQLinterColorModeDisable)
+		out := pluginTestWithContext(t, tt.rule.Expr.StrVal, tt.rule.Context(), p)
+
+		if tt.expected == nil {
+			assert.Empty(t, out, tt.rule.Location())
+		}
+		for _, s := range tt.expected {
+			assert.Contains(t, out, s, tt.rule.Location())
+		}
+	}
+
+	// the duplicate alert isn't reported twice for the same expression.
+	p := plugin.NewDuplicateRulePlugin(rulegraph.New(rules), linter.PromQLinterColorModeDisable)
+	out := pluginTestWithContext(t, a.Rules[0].Expr.StrVal, a.Rules[0].Context(), p)
+	assert.NotContains(t, out, "the same expression")
+
+	// ad-hoc queries are never reported.
+	p = plugin.NewDuplicateRulePlugin(rulegraph.New(rules), linter.PromQLinterColorModeDisable)
+	out = pluginTest(t, `sum by (job) (up)`, p)
+	assert.Empty(t, out)
+}
+
+func TestDuplicateRulesDiagnostics(t *testing.T) {
+	a, err := manifest.Parse("a.yaml", []byte(duplicateRulesManifestA))
+	assert.NoError(t, err)
+	b, err := manifest.Parse("b.yaml", []byte(duplicateRulesManifestB))
+	assert.NoError(t, err)
+	rules := append(a.Rules, b.Rules...)
+
+	tests := []struct {
+		rule     *manifest.Rule
+		expected []reported
+	}{
+		// the whole expression of the rule is pointed.
+		{b.Rules[0], []reported{{codes.DuplicateAlert, linter.DiagnosticLevelWarning, `(sum(rate(errors_total[5m])) > 1)`}}},
+		{b.Rules[2], []reported{{codes.DuplicateRecordingRule, linter.DiagnosticLevelError, `count by (job) (up)`}}},
+		{a.Rules[2], []reported{{codes.DuplicateExpression, linter.DiagnosticLevelWarning, `up == 0`}}},
+		// the alerts with the different labels are the variants.
+		{b.Rules[1], []reported{}},
+	}
+
+	for _, tt := range tests {
+		p := plugin.NewDuplicateRulePlugin(rulegraph.New(rules), linter.PromQLinterColorModeDisable)
+		assert.Equal(t, tt.expected, pluginDiagnostics(t, tt.rule.Expr.StrVal, tt.rule.Context(), p), tt.rule.Location())
+	}
+}
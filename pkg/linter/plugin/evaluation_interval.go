@@ -0,0 +1,178 @@
+/*
+MIT License
+
+# Copyright (c) 2022 Drumato
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package plugin
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/Drumato/promqlinter/pkg/codes"
+	"github.com/Drumato/promqlinter/pkg/linter"
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/promql/parser"
+)
+
+type evaluationInterval struct {
+	// defaultInterval is the global evaluation interval.
+	// it's used for the groups that don't specify the interval and the subqueries without the step.
+	defaultInterval model.Duration
+	color           linter.PromQLinterColorMode
+}
+
+// Execute implements linter.PromQLinterPlugin
+func (e *evaluationInterval) Execute(expr parser.Expr) (linter.Diagnostics, error) {
+	return e.ExecuteWithContext(expr, &linter.ExprContext{Kind: linter.ExprKindQuery})
+}
+
+// ExecuteWithContext implements linter.PromQLinterContextPlugin
+func (e *evaluationInterval) ExecuteWithContext(
+	expr parser.Expr,
+	ctx *linter.ExprContext,
+) (linter.Diagnostics, error) {
+	ds := linter.NewDiagnostics()
+	if ctx.Kind == linter.ExprKindQuery {
+		// ad-hoc queries are not evaluated periodically.
+		return ds, nil
+	}
+
+	interval := time.Duration(e.defaultInterval)
+	if ctx.GroupInterval != "" {
+		d, err := model.ParseDuration(ctx.GroupInterval)
+		if err != nil {
+			msg := fmt.Sprintf("the interval `%s` of the group `%s` is invalid: %s", ctx.GroupInterval, ctx.GroupName, err)
+			ds.Add(linter.ErrorDiagnostic(expr.PositionRange(), msg, e.color).WithCode(codes.InvalidDuration))
+			return ds, nil
+		}
+		interval = time.Duration(d)
+	}
+	if interval <= 0 {
+		return ds, nil
+	}
+
+	parser.Inspect(expr, func(n parser.Node, path []parser.Node) error {
+		switch node := n.(type) {
+		case *parser.MatrixSelector:
+			step, stepName := e.evaluationStep(path, interval)
+			if node.Range < step {
+				msg := fmt.Sprintf(
+					"the range `%s` is shorter than %s; the samples between the evaluations are never read",
+					model.Duration(node.Range), stepName,
+				)
+				ds.Add(linter.WarningDiagnostic(node.PositionRange(), msg, e.color).WithCode(codes.RangeShorterThanInterval))
+			}
+
+			return nil
+		case *parser.SubqueryExpr:
+			step, stepName := e.evaluationStep(path, interval)
+			if node.Range < step {
+				msg := fmt.Sprintf(
+					"the subquery range `%s` is shorter than %s; the samples between the evaluations are never read",
+					model.Duration(node.Range), stepName,
+				)
+				ds.Add(linter.WarningDiagnostic(node.PositionRange(), msg, e.color).WithCode(codes.RangeShorterThanInterval))
+			}
+
+			if node.Step != 0 && step%node.Step != 0 && node.Step%step != 0 {
+				msg := fmt.Sprintf(
+					"the subquery step `%s` is not aligned with %s",
+					model.Duration(node.Step), stepName,
+				)
+				ds.Add(linter.WarningDiagnostic(node.PositionRange(), msg, e.color).WithCode(codes.SubqueryStepMisaligned))
+			}
+
+			return nil
+		default:
+			// traverse all the non-nil children.
+			return nil
+		}
+	})
+
+	for _, field := range []struct {
+		name  string
+		value string
+	}{
+		{"for", ctx.For},
+		{"keep_firing_for", ctx.KeepFiringFor},
+	} {
+		if field.value == "" {
+			continue
+		}
+
+		d, err := model.ParseDuration(field.value)
+		if err != nil {
+			msg := fmt.Sprintf("`%s: %s` is invalid: %s", field.name, field.value, err)
+			ds.Add(linter.ErrorDiagnostic(expr.PositionRange(), msg, e.color).WithCode(codes.InvalidDuration))
+			continue
+		}
+
+		if time.Duration(d)%interval != 0 {
+			actual := (time.Duration(d)/interval + 1) * interval
+			msg := fmt.Sprintf(
+				"`%s: %s` is not a multiple of the evaluation interval `%s`; it actually takes `%s`",
+				field.name, field.value, model.Duration(interval), model.Duration(actual),
+			)
+			ds.Add(linter.WarningDiagnostic(expr.PositionRange(), msg, e.color).WithCode(codes.DurationNotMultipleOfInterval))
+		}
+	}
+
+	return ds, nil
+}
+
+// evaluationStep returns the step that the node under the path is evaluated at.
+// the nodes in a subquery are evaluated at the step of the innermost subquery, not at the interval of the group.
+// the subquery without the step is evaluated at the global evaluation interval.
+func (e *evaluationInterval) evaluationStep(path []parser.Node, interval time.Duration) (time.Duration, string) {
+	for i := len(path) - 1; i >= 0; i-- {
+		sq, ok := path[i].(*parser.SubqueryExpr)
+		if !ok {
+			continue
+		}
+
+		if sq.Step == 0 {
+			return time.Duration(e.defaultInterval), fmt.Sprintf(
+				"the global evaluation interval `%s` that the enclosing subquery uses as the step",
+				e.defaultInterval,
+			)
+		}
+
+		return sq.Step, fmt.Sprintf("the step `%s` of the enclosing subquery", model.Duration(sq.Step))
+	}
+
+	return interval, fmt.Sprintf("the evaluation interval `%s`", model.Duration(interval))
+}
+
+// Name implements linter.PromQLinterPlugin
+func (*evaluationInterval) Name() string {
+	return "evaluation-interval"
+}
+
+// NewEvaluationIntervalPlugin creates an evaluation-interval plugin.
+// defaultInterval is used for the groups that don't specify the interval.
+func NewEvaluationIntervalPlugin(
+	defaultInterval model.Duration,
+	color linter.PromQLinterColorMode,
+) linter.PromQLinterContextPlugin {
+	return &evaluationInterval{defaultInterval, color}
+}
@@ -0,0 +1,101 @@
+/*
+MIT License
+
+# Copyright (c) 2022 Drumato
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package plugin_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Drumato/promqlinter/pkg/codes"
+	"github.com/Drumato/promqlinter/pkg/linter"
+	"github.com/Drumato/promqlinter/pkg/linter/plugin"
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEvaluationInterval(t *testing.T) {
+	tests := []struct {
+		expr     string
+		ctx      *linter.ExprContext
+		expected []string
+	}{
+		{`rate(x[30s])`, &linter.ExprContext{Kind: linter.ExprKindQuery}, nil},
+		{`rate(x[5m]) > 0`, &linter.ExprContext{Kind: linter.ExprKindAlertingRule, For: "5m", KeepFiringFor: "10m"}, nil},
+		{`max_over_time(rate(x[5m])[1h:30s])`, &linter.ExprContext{Kind: linter.ExprKindRecordingRule}, nil},
+		{`rate(x[30s])`, &linter.ExprContext{Kind: linter.ExprKindRecordingRule}, []string{"[WARN]", "the range `30s` is shorter than the evaluation interval `1m`"}},
+		{`rate(x[1m])`, &linter.ExprContext{Kind: linter.ExprKindRecordingRule, GroupInterval: "2m"}, []string{"[WARN]", "the range `1m` is shorter than the evaluation interval `2m`"}},
+		{`max_over_time(rate(x[5m])[30s:10s])`, &linter.ExprContext{Kind: linter.ExprKindRecordingRule}, []string{"[WARN]", "the subquery range `30s` is shorter than the evaluation interval `1m`"}},
+		{`max_over_time(rate(x[30s])[1h:10s])`, &linter.ExprContext{Kind: linter.ExprKindRecordingRule}, nil},
+		{`max_over_time(rate(x[30s])[1h:])`, &linter.ExprContext{Kind: linter.ExprKindRecordingRule}, []string{"[WARN]", "the range `30s` is shorter than the global evaluation interval `1m` that the enclosing subquery uses as the step"}},
+		{`max_over_time(rate(x[1m])[1h:])`, &linter.ExprContext{Kind: linter.ExprKindRecordingRule, GroupInterval: "2m"}, nil},
+		{`max_over_time(rate(x[30s])[1h:])`, &linter.ExprContext{Kind: linter.ExprKindRecordingRule, GroupInterval: "10s"}, []string{"[WARN]", "the range `30s` is shorter than the global evaluation interval `1m`"}},
+		{`max_over_time(rate(x[5s])[1h:10s])`, &linter.ExprContext{Kind: linter.ExprKindRecordingRule}, []string{"[WARN]", "the range `5s` is shorter than the step `10s` of the enclosing subquery"}},
+		{`max_over_time(max_over_time(rate(x[1m])[5m:15s])[1h:10s])`, &linter.ExprContext{Kind: linter.ExprKindRecordingRule}, []string{"[WARN]", "the subquery step `15s` is not aligned with the step `10s` of the enclosing subquery"}},
+		{`max_over_time(rate(x[5m])[1h:45s])`, &linter.ExprContext{Kind: linter.ExprKindRecordingRule}, []string{"[WARN]", "the subquery step `45s` is not aligned with the evaluation interval `1m`"}},
+		{`up == 0`, &linter.ExprContext{Kind: linter.ExprKindAlertingRule, For: "90s"}, []string{"[WARN]", "`for: 90s` is not a multiple of the evaluation interval `1m`; it actually takes `2m`"}},
+		{`up == 0`, &linter.ExprContext{Kind: linter.ExprKindAlertingRule, KeepFiringFor: "5m", GroupInterval: "2m"}, []string{"[WARN]", "`keep_firing_for: 5m` is not a multiple of the evaluation interval `2m`; it actually takes `6m`"}},
+		{`up == 0`, &linter.ExprContext{Kind: linter.ExprKindAlertingRule, For: "5 minutes"}, []string{"[ERROR]", "`for: 5 minutes` is invalid"}},
+		{`up == 0`, &linter.ExprContext{Kind: linter.ExprKindAlertingRule, GroupName: "g", GroupInterval: "1 minute"}, []string{"[ERROR]", "the interval `1 minute` of the group `g` is invalid"}},
+	}
+
+	for _, tt := range tests {
+		p := plugin.NewEvaluationIntervalPlugin(model.Duration(time.Minute), linter.PromQLinterColorModeDisable)
+		out := pluginTestWithContext(t, tt.expr, tt.ctx, p)
+
+		if tt.expected == nil {
+			assert.Empty(t, out, tt.expr)
+		}
+		for _, s := range tt.expected {
+			assert.Contains(t, out, s, tt.expr)
+		}
+	}
+}
+
+func TestEvaluationIntervalDiagnostics(t *testing.T) {
+	recording := &linter.ExprContext{Kind: linter.ExprKindRecordingRule}
+
+	tests := []struct {
+		expr     string
+		ctx      *linter.ExprContext
+		expected []reported
+	}{
+		{`sum(rate(x[59s]))`, recording, []reported{{codes.RangeShorterThanInterval, linter.DiagnosticLevelWarning, `x[59s]`}}},
+		{`max_over_time(rate(x[5m])[30s:10s])`, recording, []reported{{codes.RangeShorterThanInterval, linter.DiagnosticLevelWarning, `rate(x[5m])[30s:10s]`}}},
+		{`max_over_time(max_over_time(rate(x[1m])[5m:15s])[1h:10s])`, recording, []reported{{codes.SubqueryStepMisaligned, linter.DiagnosticLevelWarning, `rate(x[1m])[5m:15s]`}}},
+		// the rule options are reported on the whole expression.
+		{`up == 0`, &linter.ExprContext{Kind: linter.ExprKindAlertingRule, For: "90s"}, []reported{{codes.DurationNotMultipleOfInterval, linter.DiagnosticLevelWarning, `up == 0`}}},
+		{`up == 0`, &linter.ExprContext{Kind: linter.ExprKindAlertingRule, For: "5 minutes"}, []reported{{codes.InvalidDuration, linter.DiagnosticLevelError, `up == 0`}}},
+		// the boundaries are allowed.
+		{`rate(x[1m])`, recording, []reported{}},
+		{`max_over_time(rate(x[5m])[1h:2m])`, recording, []reported{}},
+		{`up == 0`, &linter.ExprContext{Kind: linter.ExprKindAlertingRule, For: "2m"}, []reported{}},
+		{`up == 0`, &linter.ExprContext{Kind: linter.ExprKindAlertingRule, For: "0s"}, []reported{}},
+	}
+
+	for _, tt := range tests {
+		p := plugin.NewEvaluationIntervalPlugin(model.Duration(time.Minute), linter.PromQLinterColorModeDisable)
+		assert.Equal(t, tt.expected, pluginDiagnostics(t, tt.expr, tt.ctx, p), tt.expr)
+	}
+}
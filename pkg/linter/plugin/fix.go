@@ -0,0 +1,71 @@
+/*
+MIT License
+
+# Copyright (c) 2022 Drumato
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package plugin
+
+import (
+	"github.com/Drumato/promqlinter/pkg/linter"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/promql/parser"
+)
+
+// replaceMatcherFix suggests replacing the label matcher of the selector with the replacement.
+// the matcher is removed if the replacement is nil.
+// path is the ancestors of the selector, which are given by parser.Inspect().
+// it returns nil if the selector has no matchers after the fix.
+func replaceMatcherFix(
+	description string,
+	node *parser.VectorSelector,
+	path []parser.Node,
+	lm *labels.Matcher,
+	replacement *labels.Matcher,
+) *linter.SuggestedFix {
+	vs := *node
+	vs.LabelMatchers = make([]*labels.Matcher, 0, len(node.LabelMatchers))
+	for _, m := range node.LabelMatchers {
+		switch {
+		case m != lm:
+			vs.LabelMatchers = append(vs.LabelMatchers, m)
+		case replacement != nil:
+			vs.LabelMatchers = append(vs.LabelMatchers, replacement)
+		}
+	}
+
+	if vs.Name == "" && len(vs.LabelMatchers) == 0 {
+		// `{}` is not a valid selector.
+		return nil
+	}
+
+	// the position of the selector in a range selector doesn't cover the modifiers,
+	// which are rendered after the range.
+	if len(path) != 0 {
+		if _, ok := path[len(path)-1].(*parser.MatrixSelector); ok {
+			vs.OriginalOffset = 0
+			vs.Timestamp = nil
+			vs.StartOrEnd = 0
+		}
+	}
+
+	return linter.NewReplaceFix(description, node.PosRange, vs.String())
+}
@@ -0,0 +1,129 @@
+/*
+MIT License
+
+# Copyright (c) 2022 Drumato
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package plugin_test
+
+import (
+	"testing"
+
+	"github.com/Drumato/promqlinter/pkg/linter"
+	"github.com/Drumato/promqlinter/pkg/linter/plugin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSuggestedFixes(t *testing.T) {
+	color := linter.PromQLinterColorModeDisable
+	deniedLabels := plugin.DefaultConfig()
+	deniedLabels.AddDeniedLabels("job %PAIR% node_exporter")
+
+	tests := []struct {
+		expr     string
+		ctx      *linter.ExprContext
+		p        linter.PromQLinterPlugin
+		expected []string
+	}{
+		{
+			expr:     `up{job="node_exporter", instance="a"}`,
+			p:        plugin.DefaultsWithConfig(deniedLabels, color)[0],
+			expected: []string{"fix: remove `job=\"node_exporter\"`", `+ L1| up{instance="a"}`},
+		},
+		{
+			expr:     `{job="node_exporter"}`,
+			p:        plugin.DefaultsWithConfig(deniedLabels, color)[0],
+			expected: nil,
+		},
+		{
+			expr:     `up{job="a", instance=~".*"}`,
+			p:        plugin.NewLabelMatcherPlugin(color),
+			expected: []string{"fix: remove `instance=~\".*\"`", `+ L1| up{job="a"}`},
+		},
+		{
+			expr:     `rate(up{job=~"api"}[5m] offset 1h)`,
+			p:        plugin.NewRegexMatcherPlugin(color),
+			expected: []string{"fix: replace `job=~\"api\"` with `job=\"api\"`", `+ L1| rate(up{job="api"}[5m] offset 1h)`},
+		},
+		{
+			expr:     `up{job=~"^(a|b)$"} offset 1h`,
+			p:        plugin.NewRegexMatcherPlugin(color),
+			expected: []string{"fix: replace `job=~\"^(a|b)$\"` with `job=~\"a|b\"`", `+ L1| up{job=~"a|b"} offset 1h`},
+		},
+		{
+			expr:     `x * (2 * 3)`,
+			p:        plugin.NewConstantExprPlugin(color),
+			expected: []string{"fix: replace with `6`", `+ L1| x * 6`},
+		},
+		{
+			expr:     `errors / requests`,
+			p:        plugin.NewDivisionByZeroPlugin(color),
+			expected: []string{"fix: guard the denominator with `> 0`", `+ L1| errors / (requests > 0)`},
+		},
+		{
+			expr:     `errors / (requests >= 0)`,
+			p:        plugin.NewDivisionByZeroPlugin(color),
+			expected: nil,
+		},
+		{
+			expr:     `errors / (requests < 10)`,
+			p:        plugin.NewDivisionByZeroPlugin(color),
+			expected: nil,
+		},
+		{
+			expr:     `sort_desc(sum by (job) (up))`,
+			ctx:      &linter.ExprContext{Kind: linter.ExprKindRecordingRule},
+			p:        plugin.NewRankingPlugin(color),
+			expected: []string{"fix: remove `sort_desc`", `+ L1| sum by (job) (up)`},
+		},
+		{
+			expr:     `sum by () (up) + sum by (job, job) (up)`,
+			p:        plugin.NewAggregationGroupingPlugin(plugin.AggregationGroupingConfig{}, nil, color),
+			expected: []string{`+ L1| sum(up) + sum by (job, job) (up)`, `+ L1| sum by () (up) + sum by (job) (up)`},
+		},
+		{
+			expr:     "rate(x[5m]) * 300\n  > 1",
+			p:        plugin.NewSimplifyPlugin(color),
+			expected: []string{"- L1| rate(x[5m]) * 300", "+ L1| increase(x[5m])"},
+		},
+		{
+			// the closing parenthesis of `abs` is kept.
+			expr:     `abs(sum(sum by (job) (up)))`,
+			p:        plugin.NewSimplifyPlugin(color),
+			expected: []string{"+ L1| abs(sum(up))\n"},
+		},
+	}
+
+	for _, tt := range tests {
+		ctx := tt.ctx
+		if ctx == nil {
+			ctx = &linter.ExprContext{Kind: linter.ExprKindQuery}
+		}
+		out := pluginTestWithContext(t, tt.expr, ctx, tt.p)
+
+		if tt.expected == nil {
+			assert.NotContains(t, out, "fix:", tt.expr)
+		}
+		for _, s := range tt.expected {
+			assert.Contains(t, out, s, tt.expr)
+		}
+	}
+}
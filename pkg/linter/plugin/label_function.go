@@ -0,0 +1,209 @@
+/*
+MIT License
+
+# Copyright (c) 2022 Drumato
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package plugin
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/Drumato/promqlinter/pkg/codes"
+	"github.com/Drumato/promqlinter/pkg/linter"
+	"github.com/Drumato/promqlinter/pkg/promqlutil"
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/promql/parser"
+)
+
+// replacementRefPattern matches the references like `$1`, `${1}`, `$name` and `$$`.
+var replacementRefPattern = regexp.MustCompile(`\$(\$|\{([^}]*)\}|[a-zA-Z0-9_]*)`)
+
+type labelFunction struct {
+	color linter.PromQLinterColorMode
+}
+
+// Execute implements linter.PromQLinterPlugin
+func (l *labelFunction) Execute(expr parser.Expr) (linter.Diagnostics, error) {
+	ds := linter.NewDiagnostics()
+	parser.Inspect(expr, func(n parser.Node, path []parser.Node) error {
+		switch node := n.(type) {
+		case *parser.Call:
+			switch node.Func.Name {
+			case "label_replace":
+				for _, d := range l.checkLabelReplace(node) {
+					ds.Add(d)
+				}
+			case "label_join":
+				for _, d := range l.checkLabelJoin(node) {
+					ds.Add(d)
+				}
+			}
+
+			return nil
+		default:
+			// traverse all the non-nil children.
+			return nil
+		}
+	})
+
+	return ds, nil
+}
+
+// checkLabelReplace checks `label_replace(v, dst, replacement, src, regex)`.
+func (l *labelFunction) checkLabelReplace(node *parser.Call) []linter.Diagnostic {
+	ds := []linter.Diagnostic{}
+	dst := stringArg(node, 1)
+	replacement := stringArg(node, 2)
+	src := stringArg(node, 3)
+	regex := stringArg(node, 4)
+
+	ds = append(ds, l.checkLabelName(node, "destination", dst)...)
+
+	re, err := regexp.Compile("^(?:" + regex + ")$")
+	if err != nil {
+		msg := fmt.Sprintf("invalid regular expression `%s` in `label_replace`: %s", regex, err)
+		ds = append(ds, linter.ErrorDiagnostic(node.PosRange, msg, l.color).WithCode(codes.InvalidLabelReplaceRegex))
+	} else {
+		ds = append(ds, l.checkReplacementRefs(node, re, replacement)...)
+	}
+
+	if src != "" {
+		ds = append(ds, l.checkSourceLabel(node, src)...)
+	}
+
+	return ds
+}
+
+// checkLabelJoin checks `label_join(v, dst, separator, src...)`.
+func (l *labelFunction) checkLabelJoin(node *parser.Call) []linter.Diagnostic {
+	ds := []linter.Diagnostic{}
+	ds = append(ds, l.checkLabelName(node, "destination", stringArg(node, 1))...)
+
+	for i := 3; i < len(node.Args); i++ {
+		src := stringArg(node, i)
+		ds = append(ds, l.checkLabelName(node, "source", src)...)
+		ds = append(ds, l.checkSourceLabel(node, src)...)
+	}
+
+	return ds
+}
+
+// checkLabelName reports the invalid label name.
+func (l *labelFunction) checkLabelName(node *parser.Call, role, name string) []linter.Diagnostic {
+	if model.LabelName(name).IsValid() {
+		return nil
+	}
+
+	msg := fmt.Sprintf("invalid %s label name `%s` in `%s`", role, name, node.Func.Name)
+	return []linter.Diagnostic{linter.ErrorDiagnostic(node.PosRange, msg, l.color).WithCode(codes.InvalidLabelName)}
+}
+
+// checkReplacementRefs reports the references to the missing capture groups.
+func (l *labelFunction) checkReplacementRefs(
+	node *parser.Call,
+	re *regexp.Regexp,
+	replacement string,
+) []linter.Diagnostic {
+	ds := []linter.Diagnostic{}
+	for _, m := range replacementRefPattern.FindAllStringSubmatch(replacement, -1) {
+		ref := m[1]
+		if ref == "$" {
+			continue
+		}
+		if strings.HasPrefix(ref, "{") {
+			ref = m[2]
+		}
+		if ref == "" {
+			// the malformed reference is kept as it is.
+			continue
+		}
+
+		if n, err := strconv.Atoi(ref); err == nil {
+			if n > re.NumSubexp() {
+				msg := fmt.Sprintf(
+					"`%s` refers to the capture group %d but the regex has only %d",
+					m[0], n, re.NumSubexp(),
+				)
+				ds = append(ds, linter.WarningDiagnostic(node.PosRange, msg, l.color).WithCode(codes.MissingCaptureGroup))
+			}
+
+			continue
+		}
+
+		if re.SubexpIndex(ref) != -1 {
+			continue
+		}
+
+		msg := fmt.Sprintf("`%s` refers to the capture group named `%s`, which doesn't exist", m[0], ref)
+		if digits := leadingDigits(ref); digits != "" {
+			msg = fmt.Sprintf("%s; use `${%s}%s` instead", msg, digits, ref[len(digits):])
+		}
+		ds = append(ds, linter.WarningDiagnostic(node.PosRange, msg, l.color).WithCode(codes.MissingCaptureGroup))
+	}
+
+	return ds
+}
+
+// checkSourceLabel reports the source label that the input never has.
+func (l *labelFunction) checkSourceLabel(node *parser.Call, src string) []linter.Diagnostic {
+	if promqlutil.InferLabels(node.Args[0]).MayHave(src) {
+		return nil
+	}
+
+	msg := fmt.Sprintf("the source label `%s` doesn't exist on the input of `%s`", src, node.Func.Name)
+	return []linter.Diagnostic{linter.WarningDiagnostic(node.PosRange, msg, l.color).WithCode(codes.MissingSourceLabel)}
+}
+
+// Name implements linter.PromQLinterPlugin
+func (*labelFunction) Name() string {
+	return "label-functions"
+}
+
+// NewLabelFunctionPlugin creates a label-functions plugin.
+func NewLabelFunctionPlugin(
+	color linter.PromQLinterColorMode,
+) linter.PromQLinterPlugin {
+	return &labelFunction{color}
+}
+
+// stringArg returns the value of the i-th string argument of the call.
+func stringArg(node *parser.Call, i int) string {
+	if s, ok := unwrapParenExpr(node.Args[i]).(*parser.StringLiteral); ok {
+		return s.Val
+	}
+
+	return ""
+}
+
+// leadingDigits returns the digits at the beginning of s.
+func leadingDigits(s string) string {
+	for i, c := range s {
+		if c < '0' || c > '9' {
+			return s[:i]
+		}
+	}
+
+	return s
+}
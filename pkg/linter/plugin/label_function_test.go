@@ -0,0 +1,87 @@
+/*
+MIT License
+
+# Copyright (c) 2022 Drumato
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package plugin_test
+
+import (
+	"testing"
+
+	"github.com/Drumato/promqlinter/pkg/codes"
+	"github.com/Drumato/promqlinter/pkg/linter"
+	"github.com/Drumato/promqlinter/pkg/linter/plugin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLabelFunctions(t *testing.T) {
+	tests := []struct {
+		expr     string
+		expected []string
+	}{
+		{`label_replace(up, "host", "$1", "instance", "(.*):.*")`, nil},
+		{`label_replace(up, "host", "${name}", "instance", "(?P<name>.*):.*")`, nil},
+		{`label_replace(up, "host", "$$1", "instance", ".*")`, nil},
+		{`label_join(up, "id", "-", "job", "instance")`, nil},
+		{`label_replace(up, "host", "$1", "instance", "(.*")`, []string{"[ERROR]", "invalid regular expression `(.*`"}},
+		{`label_replace(up, "0host", "$1", "instance", "(.*)")`, []string{"[ERROR]", "invalid destination label name `0host`"}},
+		{`label_join(up, "id", "-", "job-name")`, []string{"[ERROR]", "invalid source label name `job-name`"}},
+		{`label_replace(up, "host", "$2", "instance", "(.*):.*")`, []string{"[WARN]", "`$2` refers to the capture group 2 but the regex has only 1"}},
+		{`label_replace(up, "host", "$1x", "instance", "(.*)")`, []string{"[WARN]", "`$1x` refers to the capture group named `1x`", "use `${1}x` instead"}},
+		{`label_replace(sum by (job) (up), "host", "$1", "instance", "(.*)")`, []string{"[WARN]", "the source label `instance` doesn't exist on the input of `label_replace`"}},
+		{`label_join(sum without (instance) (up), "id", "-", "job", "instance")`, []string{"the source label `instance` doesn't exist"}},
+	}
+
+	for _, tt := range tests {
+		p := plugin.NewLabelFunctionPlugin(linter.PromQLinterColorModeDisable)
+		out := pluginTest(t, tt.expr, p)
+
+		if tt.expected == nil {
+			assert.Empty(t, out, tt.expr)
+		}
+		for _, s := range tt.expected {
+			assert.Contains(t, out, s, tt.expr)
+		}
+	}
+}
+
+func TestLabelFunctionsDiagnostics(t *testing.T) {
+	tests := []struct {
+		expr     string
+		expected []reported
+	}{
+		{`sum(label_replace(up, "host", "$1", "instance", "(.*"))`, []reported{{codes.InvalidLabelReplaceRegex, linter.DiagnosticLevelError, `label_replace(up, "host", "$1", "instance", "(.*")`}}},
+		{`label_join(up, "id", "-", "job-name")`, []reported{{codes.InvalidLabelName, linter.DiagnosticLevelError, `label_join(up, "id", "-", "job-name")`}}},
+		{`label_replace(up, "host", "$2", "instance", "(.*):.*")`, []reported{{codes.MissingCaptureGroup, linter.DiagnosticLevelWarning, `label_replace(up, "host", "$2", "instance", "(.*):.*")`}}},
+		{`label_replace(sum by (job) (up), "host", "$1", "instance", "(.*)")`, []reported{{codes.MissingSourceLabel, linter.DiagnosticLevelWarning, `label_replace(sum by (job) (up), "host", "$1", "instance", "(.*)")`}}},
+		// the last capture group, the whole match and the named groups exist.
+		{`label_replace(up, "host", "$2", "instance", "(.*)(x)")`, []reported{}},
+		{`label_replace(up, "host", "$0", "instance", ".*")`, []reported{}},
+		{`label_replace(up, "host", "$name", "instance", "(?P<name>.*)")`, []reported{}},
+		{`label_replace(sum by (instance) (up), "host", "$1", "instance", "(.*)")`, []reported{}},
+	}
+
+	for _, tt := range tests {
+		p := plugin.NewLabelFunctionPlugin(linter.PromQLinterColorModeDisable)
+		assert.Equal(t, tt.expected, pluginDiagnostics(t, tt.expr, nil, p), tt.expr)
+	}
+}
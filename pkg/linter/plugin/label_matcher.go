@@ -0,0 +1,169 @@
+/*
+MIT License
+
+# Copyright (c) 2022 Drumato
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package plugin
+
+import (
+	"fmt"
+	"regexp/syntax"
+
+	"github.com/Drumato/promqlinter/pkg/codes"
+	"github.com/Drumato/promqlinter/pkg/linter"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/promql/parser"
+)
+
+type labelMatcher struct {
+	color linter.PromQLinterColorMode
+}
+
+// Execute implements linter.PromQLinterPlugin
+func (l *labelMatcher) Execute(expr parser.Expr) (linter.Diagnostics, error) {
+	ds := linter.NewDiagnostics()
+	parser.Inspect(expr, func(n parser.Node, path []parser.Node) error {
+		switch node := n.(type) {
+		case *parser.VectorSelector:
+			for _, matchers := range groupMatchersByName(node.LabelMatchers) {
+				for _, d := range l.checkMatchers(node, path, matchers) {
+					ds.Add(d)
+				}
+			}
+
+			return nil
+		default:
+			// traverse all the non-nil children.
+			return nil
+		}
+	})
+
+	return ds, nil
+}
+
+// checkMatchers reports the contradictory/redundant matchers for a label.
+// all of the given matchers must have the same label name.
+func (l *labelMatcher) checkMatchers(
+	node *parser.VectorSelector,
+	path []parser.Node,
+	matchers []*labels.Matcher,
+) []linter.Diagnostic {
+	ds := []linter.Diagnostic{}
+
+	var eq *labels.Matcher
+	for _, lm := range matchers {
+		if lm.Type != labels.MatchEqual {
+			continue
+		}
+
+		if eq != nil && eq.Value != lm.Value {
+			msg := fmt.Sprintf("`%s` contradicts `%s`; the selector never matches", lm, eq)
+			return append(ds, linter.ErrorDiagnostic(node.PosRange, msg, l.color).WithCode(codes.ContradictoryMatchers))
+		}
+
+		if eq == nil {
+			eq = lm
+		}
+	}
+
+	if eq == nil {
+		for _, lm := range matchers {
+			if !matchesAnyValue(lm) {
+				continue
+			}
+
+			if lm.Type == labels.MatchRegexp {
+				msg := fmt.Sprintf("`%s` matches any value; the matcher is redundant", lm)
+				fix := replaceMatcherFix(fmt.Sprintf("remove `%s`", lm), node, path, lm, nil)
+				ds = append(ds, linter.WarningDiagnostic(node.PosRange, msg, l.color).WithCode(codes.RedundantMatcher).WithSuggestedFix(fix))
+			} else {
+				msg := fmt.Sprintf("`%s` rejects any value; the selector never matches", lm)
+				ds = append(ds, linter.ErrorDiagnostic(node.PosRange, msg, l.color).WithCode(codes.ContradictoryMatchers))
+			}
+		}
+
+		return ds
+	}
+
+	for _, lm := range matchers {
+		if lm == eq {
+			continue
+		}
+
+		if !lm.Matches(eq.Value) {
+			msg := fmt.Sprintf("`%s` contradicts `%s`; the selector never matches", lm, eq)
+			ds = append(ds, linter.ErrorDiagnostic(node.PosRange, msg, l.color).WithCode(codes.ContradictoryMatchers))
+			continue
+		}
+
+		msg := fmt.Sprintf("`%s` is redundant with `%s`", lm, eq)
+		fix := replaceMatcherFix(fmt.Sprintf("remove `%s`", lm), node, path, lm, nil)
+		ds = append(ds, linter.WarningDiagnostic(node.PosRange, msg, l.color).WithCode(codes.RedundantMatcher).WithSuggestedFix(fix))
+	}
+
+	return ds
+}
+
+// Name implements linter.PromQLinterPlugin
+func (*labelMatcher) Name() string {
+	return "label-matchers"
+}
+
+// NewLabelMatcherPlugin creates a label-matchers plugin.
+func NewLabelMatcherPlugin(
+	color linter.PromQLinterColorMode,
+) linter.PromQLinterPlugin {
+	return &labelMatcher{color}
+}
+
+// groupMatchersByName groups the given matchers by the label name.
+// the order of the matchers is preserved in each group.
+func groupMatchersByName(matchers []*labels.Matcher) [][]*labels.Matcher {
+	indices := map[string]int{}
+	groups := [][]*labels.Matcher{}
+	for _, lm := range matchers {
+		idx, ok := indices[lm.Name]
+		if !ok {
+			idx = len(groups)
+			indices[lm.Name] = idx
+			groups = append(groups, []*labels.Matcher{})
+		}
+
+		groups[idx] = append(groups[idx], lm)
+	}
+
+	return groups
+}
+
+// matchesAnyValue determines whether the given regex matcher is like `.*`.
+func matchesAnyValue(lm *labels.Matcher) bool {
+	if lm.Type != labels.MatchRegexp && lm.Type != labels.MatchNotRegexp {
+		return false
+	}
+
+	re, err := syntax.Parse(lm.Value, syntax.Perl)
+	if err != nil {
+		return false
+	}
+
+	return isAnyCharStar(unwrapRegexGroups(re.Simplify()))
+}
@@ -0,0 +1,84 @@
+/*
+MIT License
+
+# Copyright (c) 2022 Drumato
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package plugin_test
+
+import (
+	"testing"
+
+	"github.com/Drumato/promqlinter/pkg/codes"
+	"github.com/Drumato/promqlinter/pkg/linter"
+	"github.com/Drumato/promqlinter/pkg/linter/plugin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLabelMatchers(t *testing.T) {
+	tests := []struct {
+		expr     string
+		expected []string
+	}{
+		{`up{job="a"}`, nil},
+		{`up{job="a", job="b"}`, []string{"[ERROR]", "`job=\"b\"` contradicts `job=\"a\"`"}},
+		{`up{job="a", job!="a"}`, []string{"[ERROR]", "`job!=\"a\"` contradicts `job=\"a\"`"}},
+		{`up{job="a", job=~"b|c"}`, []string{"[ERROR]", "`job=~\"b|c\"` contradicts `job=\"a\"`"}},
+		{`up{job="a", job!=""}`, []string{"[WARN]", "`job!=\"\"` is redundant with `job=\"a\"`"}},
+		{`up{job=~".*"}`, []string{"[WARN]", "`job=~\".*\"` matches any value"}},
+		{`up{job!~".*"}`, []string{"[ERROR]", "`job!~\".*\"` rejects any value"}},
+	}
+
+	for _, tt := range tests {
+		p := plugin.NewLabelMatcherPlugin(linter.PromQLinterColorModeDisable)
+		out := pluginTest(t, tt.expr, p)
+
+		if tt.expected == nil {
+			assert.Empty(t, out, tt.expr)
+		}
+		for _, s := range tt.expected {
+			assert.Contains(t, out, s, tt.expr)
+		}
+	}
+}
+
+func TestLabelMatchersDiagnostics(t *testing.T) {
+	tests := []struct {
+		expr     string
+		expected []reported
+	}{
+		{`up{job="a", job="b"}`, []reported{{codes.ContradictoryMatchers, linter.DiagnosticLevelError, `up{job="a", job="b"}`}}},
+		{`up{job="a", job!="b"}`, []reported{{codes.RedundantMatcher, linter.DiagnosticLevelWarning, `up{job="a", job!="b"}`}}},
+		{`up{job="a", job=~"a|b"}`, []reported{{codes.RedundantMatcher, linter.DiagnosticLevelWarning, `up{job="a", job=~"a|b"}`}}},
+		{`up{job!~".*"}`, []reported{{codes.ContradictoryMatchers, linter.DiagnosticLevelError, `up{job!~".*"}`}}},
+		// only the selector with the contradiction is pointed.
+		{`sum(up{job="a"}) / sum(up{job="a", job="b"})`, []reported{{codes.ContradictoryMatchers, linter.DiagnosticLevelError, `up{job="a", job="b"}`}}},
+		// `.+` rejects the missing label so it's not redundant.
+		{`up{job=~".+"}`, []reported{}},
+		// the empty value matches the series without the label.
+		{`up{job=""}`, []reported{}},
+	}
+
+	for _, tt := range tests {
+		p := plugin.NewLabelMatcherPlugin(linter.PromQLinterColorModeDisable)
+		assert.Equal(t, tt.expected, pluginDiagnostics(t, tt.expr, nil, p), tt.expr)
+	}
+}
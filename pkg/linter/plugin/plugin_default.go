@@ -27,11 +27,40 @@ package plugin
 import "github.com/Drumato/promqlinter/pkg/linter"
 
 // Defaults returns the set of the default linter plugin.
+// deniedLabels is given in the --denied-labels flag format
+// and the other plugins use DefaultConfig(); see DefaultsWithConfig() to configure them.
 func Defaults(
 	deniedLabels string,
 	color linter.PromQLinterColorMode,
+) []linter.PromQLinterPlugin {
+	config := DefaultConfig()
+	config.AddDeniedLabels(deniedLabels)
+
+	return DefaultsWithConfig(config, color)
+}
+
+// DefaultsWithConfig returns the set of the default linter plugin that are configured by the config.
+func DefaultsWithConfig(
+	config *Config,
+	color linter.PromQLinterColorMode,
 ) []linter.PromQLinterPlugin {
 	return []linter.PromQLinterPlugin{
-		NewDeniedLabelPlugin(deniedLabels, color),
+		&deniedLabel{config.DeniedLabels, color},
+		NewLabelMatcherPlugin(color),
+		NewRegexMatcherPlugin(color),
+		NewRangeDurationPlugin(config.RangeDuration, color),
+		NewQueryCostPlugin(config.QueryCost, color),
+		NewDeniedFunctionPlugin(config.DeniedFunctions, color),
+		NewPrometheusCompatPlugin(config.PrometheusVersion, color),
+		NewAlertConditionPlugin(color),
+		NewConstantExprPlugin(color),
+		NewDivisionByZeroPlugin(color),
+		NewAbsentPlugin(color),
+		NewLabelFunctionPlugin(color),
+		NewRankingPlugin(color),
+		NewAggregationGroupingPlugin(config.AggregationGrouping, config.HighCardinalityLabels, color),
+		NewCardinalityPlugin(config.HighCardinalityLabels, color),
+		NewSimplifyPlugin(color),
+		NewEvaluationIntervalPlugin(config.EvaluationInterval, color),
 	}
 }
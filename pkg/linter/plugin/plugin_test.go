@@ -0,0 +1,119 @@
+/*
+MIT License
+
+# Copyright (c) 2022 Drumato
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package plugin_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/Drumato/promqlinter/pkg/linter"
+	"github.com/Drumato/promqlinter/pkg/promqlutil"
+	"github.com/stretchr/testify/assert"
+)
+
+// reported is the summary of a diagnostic that the tests assert on.
+type reported struct {
+	// code is the code of the check.
+	code string
+	// level is the level of the diagnostic.
+	level linter.DiagnosticLevel
+	// at is the part of the expression that the diagnostic points to.
+	at string
+}
+
+// pluginTest lints rawExpr with the given plugin and returns the reports.
+func pluginTest(
+	t *testing.T,
+	rawExpr string,
+	p linter.PromQLinterPlugin,
+) string {
+	return pluginTestWithContext(t, rawExpr, &linter.ExprContext{Kind: linter.ExprKindQuery}, p)
+}
+
+// pluginTestWithContext is the same as pluginTest but lints rawExpr with the context.
+func pluginTestWithContext(
+	t *testing.T,
+	rawExpr string,
+	ctx *linter.ExprContext,
+	p linter.PromQLinterPlugin,
+) string {
+	out := &bytes.Buffer{}
+	l := linter.New(
+		linter.WithPlugin(p),
+		linter.WithOutStream(out),
+		linter.WithANSIColorMode(linter.PromQLinterColorModeDisable),
+	)
+
+	_, err := l.ExecuteWithContext(rawExpr, ctx, linter.DiagnosticLevelInfo)
+	assert.NoError(t, err)
+
+	return out.String()
+}
+
+// pluginDiagnostics runs the plugin against rawExpr directly and returns the summaries of the diagnostics.
+// the plugin runs with the context of an ad-hoc query unless it's given.
+func pluginDiagnostics(
+	t *testing.T,
+	rawExpr string,
+	ctx *linter.ExprContext,
+	p linter.PromQLinterPlugin,
+) []reported {
+	t.Helper()
+
+	if ctx == nil {
+		ctx = &linter.ExprContext{Kind: linter.ExprKindQuery}
+	}
+
+	expr, err := promqlutil.ParseExpr(rawExpr)
+	if !assert.NoError(t, err, rawExpr) {
+		return nil
+	}
+
+	var ds linter.Diagnostics
+	if cp, ok := p.(linter.PromQLinterContextPlugin); ok {
+		ds, err = cp.ExecuteWithContext(expr, ctx)
+	} else {
+		ds, err = p.Execute(expr)
+	}
+	if !assert.NoError(t, err, rawExpr) {
+		return nil
+	}
+
+	rs := []reported{}
+	for _, d := range ds.Slice() {
+		r := reported{level: d.Level()}
+		if cd, ok := d.(linter.CodedDiagnostic); ok {
+			r.code = cd.Code()
+		}
+		if pd, ok := d.(linter.PositionedDiagnostic); ok {
+			pos := pd.Position()
+			r.at = rawExpr[pos.Start:pos.End]
+		}
+
+		rs = append(rs, r)
+	}
+
+	return rs
+}
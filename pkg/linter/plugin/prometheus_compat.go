@@ -0,0 +1,281 @@
+/*
+MIT License
+
+# Copyright (c) 2022 Drumato
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package plugin
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/Drumato/promqlinter/pkg/codes"
+	"github.com/Drumato/promqlinter/pkg/linter"
+	"github.com/prometheus/prometheus/promql/parser"
+)
+
+// promVersion is a version of Prometheus.
+type promVersion struct {
+	major, minor, patch int
+}
+
+// parsePromVersion parses the version like `2.40.1`, `v2.40` or `2.40.0-rc.0`.
+func parsePromVersion(s string) (promVersion, error) {
+	v := strings.TrimPrefix(s, "v")
+	if idx := strings.IndexAny(v, "-+"); idx != -1 {
+		v = v[:idx]
+	}
+
+	parts := strings.Split(v, ".")
+	if len(parts) > 3 {
+		return promVersion{}, fmt.Errorf("invalid Prometheus version %q", s)
+	}
+
+	nums := [3]int{}
+	for i, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil {
+			return promVersion{}, fmt.Errorf("invalid Prometheus version %q", s)
+		}
+		nums[i] = n
+	}
+
+	return promVersion{nums[0], nums[1], nums[2]}, nil
+}
+
+// less determines whether v is older than other.
+func (v promVersion) less(other promVersion) bool {
+	if v.major != other.major {
+		return v.major < other.major
+	}
+	if v.minor != other.minor {
+		return v.minor < other.minor
+	}
+
+	return v.patch < other.patch
+}
+
+// String implements fmt.Stringer
+func (v promVersion) String() string {
+	return fmt.Sprintf("%d.%d.%d", v.major, v.minor, v.patch)
+}
+
+// promFeature is a PromQL feature that is available in some Prometheus versions.
+type promFeature struct {
+	// since is the version that enables the feature by default.
+	since promVersion
+	// flaggedSince is the version that supports the feature behind featureFlag.
+	flaggedSince promVersion
+	featureFlag  string
+	// removedIn is the version that removes the feature.
+	removedIn *promVersion
+}
+
+var (
+	promFeatureAtModifier = &promFeature{
+		since:        promVersion{2, 33, 0},
+		flaggedSince: promVersion{2, 25, 0},
+		featureFlag:  "promql-at-modifier",
+	}
+	promFeatureNegativeOffset = &promFeature{
+		since:        promVersion{2, 33, 0},
+		flaggedSince: promVersion{2, 26, 0},
+		featureFlag:  "promql-negative-offset",
+	}
+	promFeatureSubquery = &promFeature{since: promVersion{2, 7, 0}}
+	promFeatureGroup    = &promFeature{since: promVersion{2, 20, 0}}
+	promFeatureAtan2    = &promFeature{since: promVersion{2, 26, 0}}
+	promFeatureTrig     = &promFeature{since: promVersion{2, 26, 0}}
+	// the functions need no feature flag although the native histograms themselves do.
+	promFeatureHistogram = &promFeature{since: promVersion{2, 40, 0}}
+)
+
+// promFunctionFeatures is the version table of the PromQL functions
+// that are not available in all Prometheus 2.x versions.
+// note that the functions that the vendored parser doesn't know (e.g., `limitk` and `limit_ratio`)
+// are reported as the parse errors instead.
+var promFunctionFeatures = map[string]*promFeature{
+	"absent_over_time":   {since: promVersion{2, 16, 0}},
+	"clamp":              {since: promVersion{2, 26, 0}},
+	"last_over_time":     {since: promVersion{2, 26, 0}},
+	"sgn":                {since: promVersion{2, 26, 0}},
+	"present_over_time":  {since: promVersion{2, 29, 0}},
+	"acos":               promFeatureTrig,
+	"acosh":              promFeatureTrig,
+	"asin":               promFeatureTrig,
+	"asinh":              promFeatureTrig,
+	"atan":               promFeatureTrig,
+	"atanh":              promFeatureTrig,
+	"cos":                promFeatureTrig,
+	"cosh":               promFeatureTrig,
+	"sin":                promFeatureTrig,
+	"sinh":               promFeatureTrig,
+	"tan":                promFeatureTrig,
+	"tanh":               promFeatureTrig,
+	"deg":                promFeatureTrig,
+	"rad":                promFeatureTrig,
+	"pi":                 promFeatureTrig,
+	"histogram_count":    promFeatureHistogram,
+	"histogram_sum":      promFeatureHistogram,
+	"histogram_fraction": promFeatureHistogram,
+	"holt_winters":       {removedIn: &promVersion{3, 0, 0}},
+}
+
+// unsupportedIn describes why the feature is not supported in the target version.
+// it returns an empty string if the target supports the feature.
+func (f *promFeature) unsupportedIn(target promVersion) string {
+	if f.removedIn != nil && !target.less(*f.removedIn) {
+		return fmt.Sprintf("is removed in Prometheus %s", f.removedIn)
+	}
+
+	if !target.less(f.since) {
+		return ""
+	}
+
+	if f.featureFlag != "" && !target.less(f.flaggedSince) {
+		return fmt.Sprintf(
+			"requires `--enable-feature=%s` before Prometheus %s",
+			f.featureFlag, f.since,
+		)
+	}
+
+	if f.featureFlag != "" {
+		return fmt.Sprintf("requires Prometheus %s or later", f.flaggedSince)
+	}
+
+	return fmt.Sprintf("requires Prometheus %s or later", f.since)
+}
+
+type prometheusCompat struct {
+	// target is the parsed target version; it's nil if the version is empty.
+	target *promVersion
+	// err is the error of parsing the target version.
+	err   error
+	color linter.PromQLinterColorMode
+}
+
+// Execute implements linter.PromQLinterPlugin
+func (p *prometheusCompat) Execute(expr parser.Expr) (linter.Diagnostics, error) {
+	ds := linter.NewDiagnostics()
+	if p.err != nil {
+		return nil, p.err
+	}
+	if p.target == nil {
+		return ds, nil
+	}
+
+	target := *p.target
+
+	report := func(pos parser.PositionRange, subject string, f *promFeature) {
+		if reason := f.unsupportedIn(target); reason != "" {
+			msg := fmt.Sprintf("%s %s (target: %s)", subject, reason, target)
+			ds.Add(linter.ErrorDiagnostic(pos, msg, p.color).WithCode(codes.IncompatibleFeature))
+		}
+	}
+
+	parser.Inspect(expr, func(n parser.Node, path []parser.Node) error {
+		switch node := n.(type) {
+		case *parser.Call:
+			if f, ok := promFunctionFeatures[node.Func.Name]; ok {
+				report(node.PosRange, fmt.Sprintf("`%s`", node.Func.Name), f)
+			}
+
+			return nil
+		case *parser.AggregateExpr:
+			if node.Op == parser.GROUP {
+				report(node.PosRange, "`group`", promFeatureGroup)
+			}
+
+			return nil
+		case *parser.BinaryExpr:
+			if node.Op == parser.ATAN2 {
+				report(node.PositionRange(), "`atan2`", promFeatureAtan2)
+			}
+
+			return nil
+		case *parser.VectorSelector:
+			pos := selectorPosition(node, path)
+			if node.Timestamp != nil || node.StartOrEnd != 0 {
+				report(pos, "the `@` modifier", promFeatureAtModifier)
+			}
+			if node.OriginalOffset < 0 {
+				report(pos, "the negative offset", promFeatureNegativeOffset)
+			}
+
+			return nil
+		case *parser.SubqueryExpr:
+			report(node.PositionRange(), "the subquery", promFeatureSubquery)
+			if node.Timestamp != nil || node.StartOrEnd != 0 {
+				report(node.PositionRange(), "the `@` modifier", promFeatureAtModifier)
+			}
+			if node.OriginalOffset < 0 {
+				report(node.PositionRange(), "the negative offset", promFeatureNegativeOffset)
+			}
+
+			return nil
+		default:
+			// traverse all the non-nil children.
+			return nil
+		}
+	})
+
+	return ds, nil
+}
+
+// Name implements linter.PromQLinterPlugin
+func (*prometheusCompat) Name() string {
+	return "prometheus-compatibility"
+}
+
+// NewPrometheusCompatPlugin creates a prometheus-compatibility plugin.
+// the plugin reports nothing if the target version is empty.
+// the invalid version is returned as the error of Execute(); see ValidatePrometheusVersion().
+func NewPrometheusCompatPlugin(
+	version string,
+	color linter.PromQLinterColorMode,
+) linter.PromQLinterPlugin {
+	p := &prometheusCompat{color: color}
+	if version == "" {
+		return p
+	}
+
+	target, err := parsePromVersion(version)
+	if err != nil {
+		p.err = err
+		return p
+	}
+	p.target = &target
+
+	return p
+}
+
+// ValidatePrometheusVersion checks the target version of the prometheus-compatibility plugin.
+// the empty version is valid.
+func ValidatePrometheusVersion(version string) error {
+	if version == "" {
+		return nil
+	}
+
+	_, err := parsePromVersion(version)
+	return err
+}
@@ -0,0 +1,107 @@
+/*
+MIT License
+
+# Copyright (c) 2022 Drumato
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package plugin_test
+
+import (
+	"testing"
+
+	"github.com/Drumato/promqlinter/pkg/codes"
+	"github.com/Drumato/promqlinter/pkg/linter"
+	"github.com/Drumato/promqlinter/pkg/linter/plugin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPrometheusCompat(t *testing.T) {
+	tests := []struct {
+		version  string
+		expr     string
+		expected []string
+	}{
+		{"", `present_over_time(up[5m])`, nil},
+		{"2.29.0", `present_over_time(up[5m])`, nil},
+		{"v2.28.1", `present_over_time(up[5m])`, []string{"[ERROR]", "`present_over_time` requires Prometheus 2.29.0 or later (target: 2.28.1)"}},
+		{"2.30", `up @ 1609746000`, []string{"the `@` modifier requires `--enable-feature=promql-at-modifier` before Prometheus 2.33.0"}},
+		{"2.24.0", `up @ start()`, []string{"the `@` modifier requires Prometheus 2.25.0 or later"}},
+		{"2.30.0", `up offset -5m`, []string{"the negative offset requires `--enable-feature=promql-negative-offset`"}},
+		{"2.6.0", `max_over_time(rate(x[5m])[1h:])`, []string{"the subquery requires Prometheus 2.7.0 or later"}},
+		{"2.19.0", `group by (job) (up)`, []string{"`group` requires Prometheus 2.20.0 or later"}},
+		{"2.25.0", `a atan2 b`, []string{"`atan2` requires Prometheus 2.26.0 or later"}},
+		{"3.0.0", `holt_winters(x[1h], 0.5, 0.5)`, []string{"`holt_winters` is removed in Prometheus 3.0.0"}},
+		{"2.39.0", `histogram_count(x)`, []string{"`histogram_count` requires Prometheus 2.40.0 or later"}},
+		{"2.40.0", `histogram_sum(x)`, nil},
+		{"2.45.0", `histogram_count(x) / histogram_sum(x)`, nil},
+		{"3.0.0", `histogram_fraction(0, 1, x)`, nil},
+	}
+
+	for _, tt := range tests {
+		assert.NoError(t, plugin.ValidatePrometheusVersion(tt.version))
+		p := plugin.NewPrometheusCompatPlugin(tt.version, linter.PromQLinterColorModeDisable)
+		out := pluginTest(t, tt.expr, p)
+
+		if tt.expected == nil {
+			assert.Empty(t, out, tt.expr)
+		}
+		for _, s := range tt.expected {
+			assert.Contains(t, out, s, tt.expr)
+		}
+	}
+}
+
+func TestPrometheusCompatDiagnostics(t *testing.T) {
+	tests := []struct {
+		version  string
+		expr     string
+		expected []reported
+	}{
+		{"2.28.1", `sum(present_over_time(up[5m]))`, []reported{{codes.IncompatibleFeature, linter.DiagnosticLevelError, `present_over_time(up[5m])`}}},
+		{"2.30.0", `up @ 1609746000`, []reported{{codes.IncompatibleFeature, linter.DiagnosticLevelError, `up @ 1609746000`}}},
+		// the modifiers of a range vector point the whole range vector.
+		{"2.30.0", `rate(up[5m] offset -5m)`, []reported{{codes.IncompatibleFeature, linter.DiagnosticLevelError, `up[5m] offset -5m`}}},
+		{"2.6.0", `max_over_time(rate(x[5m])[1h:])`, []reported{{codes.IncompatibleFeature, linter.DiagnosticLevelError, `rate(x[5m])[1h:]`}}},
+		{"2.19.0", `group by (job) (up)`, []reported{{codes.IncompatibleFeature, linter.DiagnosticLevelError, `group by (job) (up)`}}},
+		{"2.25.0", `a atan2 b`, []reported{{codes.IncompatibleFeature, linter.DiagnosticLevelError, `a atan2 b`}}},
+		{"3.0.0", `holt_winters(x[1h], 0.5, 0.5)`, []reported{{codes.IncompatibleFeature, linter.DiagnosticLevelError, `holt_winters(x[1h], 0.5, 0.5)`}}},
+		{"2.39.0", `histogram_count(x)`, []reported{{codes.IncompatibleFeature, linter.DiagnosticLevelError, `histogram_count(x)`}}},
+		// the features are available since the given versions.
+		{"2.33.0", `up @ 1609746000`, []reported{}},
+		{"2.20.0", `group by (job) (up)`, []reported{}},
+		{"2.99.0", `holt_winters(x[1h], 0.5, 0.5)`, []reported{}},
+		{"2.45.0", `histogram_count(x) / histogram_sum(x)`, []reported{}},
+	}
+
+	for _, tt := range tests {
+		p := plugin.NewPrometheusCompatPlugin(tt.version, linter.PromQLinterColorModeDisable)
+		assert.Equal(t, tt.expected, pluginDiagnostics(t, tt.expr, nil, p), tt.version+" "+tt.expr)
+	}
+}
+
+func TestPrometheusCompatInvalidVersion(t *testing.T) {
+	assert.Error(t, plugin.ValidatePrometheusVersion("2.x"))
+	assert.Error(t, (&plugin.Config{PrometheusVersion: "latest"}).Validate())
+
+	p := plugin.NewPrometheusCompatPlugin("2.x", linter.PromQLinterColorModeDisable)
+	_, err := p.Execute(nil)
+	assert.Error(t, err)
+}
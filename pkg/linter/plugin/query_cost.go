@@ -0,0 +1,207 @@
+/*
+MIT License
+
+# Copyright (c) 2022 Drumato
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package plugin
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Drumato/promqlinter/pkg/codes"
+	"github.com/Drumato/promqlinter/pkg/linter"
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/promql/parser"
+)
+
+const (
+	// defaultSubqueryStep is the step of the subqueries that omit it.
+	// Prometheus uses the global evaluation interval, which is 1m by default.
+	defaultSubqueryStep = time.Minute
+)
+
+// QueryCostConfig is the budget of the query-cost plugin.
+// a non-positive budget means no limit.
+type QueryCostConfig struct {
+	// MaxSelectors is the maximum number of the series selectors.
+	MaxSelectors int `json:"maxSelectors,omitempty"`
+	// MaxTotalRange is the maximum sum of the ranges of the range vectors.
+	MaxTotalRange model.Duration `json:"maxTotalRange,omitempty"`
+	// MaxSubquerySteps is the maximum number of the steps that the subqueries evaluate.
+	MaxSubquerySteps int `json:"maxSubquerySteps,omitempty"`
+	// MaxDepth is the maximum nesting depth of the expression.
+	MaxDepth int `json:"maxDepth,omitempty"`
+	// MaxUnanchoredRegexSelectors is the maximum number of the selectors
+	// that have regex matchers but no equality matchers.
+	MaxUnanchoredRegexSelectors int `json:"maxUnanchoredRegexSelectors,omitempty"`
+}
+
+// queryCost is the estimated cost of a PromQL expression.
+type queryCost struct {
+	selectors               int
+	totalRange              time.Duration
+	subquerySteps           int
+	depth                   int
+	unanchoredRegexSelector int
+}
+
+// String implements fmt.Stringer
+func (c *queryCost) String() string {
+	return fmt.Sprintf(
+		"selectors=%d, total-range=%s, subquery-steps=%d, depth=%d, unanchored-regex-selectors=%d",
+		c.selectors,
+		model.Duration(c.totalRange),
+		c.subquerySteps,
+		c.depth,
+		c.unanchoredRegexSelector,
+	)
+}
+
+type queryCostPlugin struct {
+	config QueryCostConfig
+	color  linter.PromQLinterColorMode
+}
+
+// Execute implements linter.PromQLinterPlugin
+func (q *queryCostPlugin) Execute(expr parser.Expr) (linter.Diagnostics, error) {
+	ds := linter.NewDiagnostics()
+	cost := estimateQueryCost(expr)
+
+	exceeded := []string{}
+	if b := q.config.MaxSelectors; b > 0 && cost.selectors > b {
+		exceeded = append(exceeded, fmt.Sprintf("selectors %d > %d", cost.selectors, b))
+	}
+	if b := q.config.MaxTotalRange; b > 0 && model.Duration(cost.totalRange) > b {
+		exceeded = append(exceeded, fmt.Sprintf("total-range %s > %s", model.Duration(cost.totalRange), b))
+	}
+	if b := q.config.MaxSubquerySteps; b > 0 && cost.subquerySteps > b {
+		exceeded = append(exceeded, fmt.Sprintf("subquery-steps %d > %d", cost.subquerySteps, b))
+	}
+	if b := q.config.MaxDepth; b > 0 && cost.depth > b {
+		exceeded = append(exceeded, fmt.Sprintf("depth %d > %d", cost.depth, b))
+	}
+	if b := q.config.MaxUnanchoredRegexSelectors; b > 0 && cost.unanchoredRegexSelector > b {
+		exceeded = append(exceeded, fmt.Sprintf("unanchored-regex-selectors %d > %d", cost.unanchoredRegexSelector, b))
+	}
+
+	if len(exceeded) != 0 {
+		msg := fmt.Sprintf(
+			"the query exceeds the cost budget (%s); estimated cost: %s",
+			strings.Join(exceeded, ", "), cost,
+		)
+		ds.Add(linter.WarningDiagnostic(expr.PositionRange(), msg, q.color).WithCode(codes.QueryCostExceeded))
+	}
+
+	return ds, nil
+}
+
+// Name implements linter.PromQLinterPlugin
+func (*queryCostPlugin) Name() string {
+	return "query-cost"
+}
+
+// NewQueryCostPlugin creates a query-cost plugin.
+func NewQueryCostPlugin(
+	config QueryCostConfig,
+	color linter.PromQLinterColorMode,
+) linter.PromQLinterPlugin {
+	return &queryCostPlugin{config, color}
+}
+
+// estimateQueryCost walks the expression and accumulates the cost.
+// the ranges and the steps in a subquery are multiplied by the steps of the subquery,
+// since the inner expression is evaluated at each step.
+func estimateQueryCost(expr parser.Expr) *queryCost {
+	cost := &queryCost{}
+	parser.Inspect(expr, func(n parser.Node, path []parser.Node) error {
+		if n == nil {
+			return nil
+		}
+
+		if depth := len(path) + 1; depth > cost.depth {
+			cost.depth = depth
+		}
+
+		evaluations := 1
+		for _, p := range path {
+			if sq, ok := p.(*parser.SubqueryExpr); ok {
+				evaluations *= subqueryStepsOf(sq)
+			}
+		}
+
+		switch node := n.(type) {
+		case *parser.VectorSelector:
+			cost.selectors++
+			if isUnanchoredRegexSelector(node) {
+				cost.unanchoredRegexSelector++
+			}
+
+			return nil
+		case *parser.MatrixSelector:
+			cost.totalRange += node.Range * time.Duration(evaluations)
+			return nil
+		case *parser.SubqueryExpr:
+			cost.subquerySteps += subqueryStepsOf(node) * evaluations
+			return nil
+		default:
+			// traverse all the non-nil children.
+			return nil
+		}
+	})
+
+	return cost
+}
+
+// subqueryStepsOf returns the number of the steps that the subquery evaluates.
+func subqueryStepsOf(node *parser.SubqueryExpr) int {
+	step := node.Step
+	if step == 0 {
+		step = defaultSubqueryStep
+	}
+
+	if steps := int(node.Range / step); steps > 1 {
+		return steps
+	}
+
+	return 1
+}
+
+// isUnanchoredRegexSelector determines whether the selector has regex matchers
+// but no equality matcher that narrows down the series.
+func isUnanchoredRegexSelector(node *parser.VectorSelector) bool {
+	hasRegex := false
+	for _, lm := range node.LabelMatchers {
+		switch lm.Type {
+		case labels.MatchEqual:
+			if lm.Value != "" {
+				return false
+			}
+		case labels.MatchRegexp, labels.MatchNotRegexp:
+			hasRegex = true
+		}
+	}
+
+	return hasRegex
+}
@@ -0,0 +1,103 @@
+/*
+MIT License
+
+# Copyright (c) 2022 Drumato
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package plugin_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Drumato/promqlinter/pkg/codes"
+	"github.com/Drumato/promqlinter/pkg/linter"
+	"github.com/Drumato/promqlinter/pkg/linter/plugin"
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQueryCost(t *testing.T) {
+	config := plugin.QueryCostConfig{
+		MaxSelectors:                2,
+		MaxTotalRange:               model.Duration(time.Hour),
+		MaxSubquerySteps:            100,
+		MaxDepth:                    4,
+		MaxUnanchoredRegexSelectors: 1,
+	}
+
+	tests := []struct {
+		expr     string
+		expected []string
+	}{
+		{`sum(rate(http_requests_total[5m]))`, nil},
+		{`a + b + c`, []string{"[WARN]", "(selectors 3 > 2)", "selectors=3, total-range=0s"}},
+		{`rate(a[45m]) / rate(b[45m])`, []string{"total-range 1h30m > 1h"}},
+		{`max_over_time(rate(a[5m])[1d:1m])`, []string{"subquery-steps 1440 > 100", "total-range 5d > 1h"}},
+		{`max_over_time(rate(a[1m])[10m:1m])`, []string{"total-range=10m", "subquery-steps=10"}},
+		{`max_over_time(max_over_time(rate(a[1m])[10m:1m])[1h:1m])`, []string{"subquery-steps 660 > 100", "total-range 10h > 1h"}},
+		{`abs(abs(abs(abs(a))))`, []string{"depth 5 > 4"}},
+		{`{__name__=~"a.*"} + {__name__=~"b.*"}`, []string{"unanchored-regex-selectors 2 > 1"}},
+	}
+
+	for _, tt := range tests {
+		p := plugin.NewQueryCostPlugin(config, linter.PromQLinterColorModeDisable)
+		out := pluginTest(t, tt.expr, p)
+
+		if tt.expected == nil {
+			assert.Empty(t, out, tt.expr)
+		}
+		for _, s := range tt.expected {
+			assert.Contains(t, out, s, tt.expr)
+		}
+	}
+}
+
+func TestQueryCostDiagnostics(t *testing.T) {
+	config := plugin.QueryCostConfig{
+		MaxSelectors:                2,
+		MaxTotalRange:               model.Duration(time.Hour),
+		MaxDepth:                    4,
+		MaxUnanchoredRegexSelectors: 1,
+	}
+
+	tests := []struct {
+		expr     string
+		expected []reported
+	}{
+		// the budgets themselves are allowed.
+		{`a + b`, []reported{}},
+		{`rate(a[30m]) / rate(b[30m])`, []reported{}},
+		{`abs(abs(abs(a)))`, []reported{}},
+		// the equality matcher narrows down the series.
+		{`{__name__=~"a.*", job="x"} + {__name__=~"b.*"}`, []reported{}},
+		// the whole expression is pointed.
+		{`a + b + c`, []reported{{codes.QueryCostExceeded, linter.DiagnosticLevelWarning, `a + b + c`}}},
+		{`sum(rate(a[45m])) / sum(rate(b[45m]))`, []reported{{codes.QueryCostExceeded, linter.DiagnosticLevelWarning, `sum(rate(a[45m])) / sum(rate(b[45m]))`}}},
+		{`abs(abs(abs(abs(a))))`, []reported{{codes.QueryCostExceeded, linter.DiagnosticLevelWarning, `abs(abs(abs(abs(a))))`}}},
+		{`{__name__=~"a.*"} + {__name__=~"b.*"}`, []reported{{codes.QueryCostExceeded, linter.DiagnosticLevelWarning, `{__name__=~"a.*"} + {__name__=~"b.*"}`}}},
+	}
+
+	for _, tt := range tests {
+		p := plugin.NewQueryCostPlugin(config, linter.PromQLinterColorModeDisable)
+		assert.Equal(t, tt.expected, pluginDiagnostics(t, tt.expr, nil, p), tt.expr)
+	}
+}
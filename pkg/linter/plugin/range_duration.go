@@ -0,0 +1,267 @@
+/*
+MIT License
+
+# Copyright (c) 2022 Drumato
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package plugin
+
+import (
+	"fmt"
+	"regexp"
+	"time"
+
+	"github.com/Drumato/promqlinter/pkg/codes"
+	"github.com/Drumato/promqlinter/pkg/linter"
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/promql/parser"
+)
+
+const (
+	jobLabelName = "job"
+)
+
+// RangeDurationConfig is the policy of the range-duration plugin.
+type RangeDurationConfig struct {
+	// ScrapeInterval is the scrape interval of the targets that no JobScrapeIntervals matches.
+	ScrapeInterval model.Duration `json:"scrapeInterval,omitempty"`
+	// JobScrapeIntervals overrides ScrapeInterval for each job.
+	JobScrapeIntervals []JobScrapeInterval `json:"jobScrapeIntervals,omitempty"`
+	// MinRateRangeFactor is the minimum range of rate-like functions
+	// as a multiple of the scrape interval.
+	MinRateRangeFactor float64 `json:"minRateRangeFactor,omitempty"`
+	// MaxRange is the maximum range of range vectors and subqueries.
+	// zero means no limit.
+	MaxRange model.Duration `json:"maxRange,omitempty"`
+	// MaxIrateRange is the maximum range of irate()/idelta().
+	// zero means no limit.
+	MaxIrateRange model.Duration `json:"maxIrateRange,omitempty"`
+}
+
+// JobScrapeInterval is the scrape interval of the jobs.
+type JobScrapeInterval struct {
+	// Job is a regex that matches the job label.
+	Job string `json:"job"`
+	// ScrapeInterval is the scrape interval of the matched jobs.
+	ScrapeInterval model.Duration `json:"scrapeInterval"`
+}
+
+// rateFunctions are the functions that need a lot of samples in the range.
+var rateFunctions = map[string]struct{}{
+	"rate":           {},
+	"increase":       {},
+	"delta":          {},
+	"deriv":          {},
+	"predict_linear": {},
+	"holt_winters":   {},
+}
+
+// instantRateFunctions are the functions that use the last two samples only.
+var instantRateFunctions = map[string]struct{}{
+	"irate":  {},
+	"idelta": {},
+}
+
+type rangeDuration struct {
+	config RangeDurationConfig
+	// jobs is the compiled config.JobScrapeIntervals.
+	jobs []jobScrapeInterval
+	// err is the error of compiling the job patterns.
+	err   error
+	color linter.PromQLinterColorMode
+}
+
+type jobScrapeInterval struct {
+	job            *regexp.Regexp
+	scrapeInterval time.Duration
+}
+
+// Execute implements linter.PromQLinterPlugin
+func (r *rangeDuration) Execute(expr parser.Expr) (linter.Diagnostics, error) {
+	if r.err != nil {
+		return nil, r.err
+	}
+
+	ds := linter.NewDiagnostics()
+	parser.Inspect(expr, func(n parser.Node, path []parser.Node) error {
+		switch node := n.(type) {
+		case *parser.MatrixSelector:
+			var call *parser.Call
+			if len(path) != 0 {
+				call, _ = path[len(path)-1].(*parser.Call)
+			}
+
+			for _, d := range r.checkMatrixSelector(node, call) {
+				ds.Add(d)
+			}
+
+			return nil
+		case *parser.SubqueryExpr:
+			for _, d := range r.checkSubquery(node) {
+				ds.Add(d)
+			}
+
+			return nil
+		default:
+			// traverse all the non-nil children.
+			return nil
+		}
+	})
+
+	return ds, nil
+}
+
+// checkMatrixSelector checks the range of a range vector
+// that is passed to the call (or nil if it isn't an argument).
+func (r *rangeDuration) checkMatrixSelector(
+	node *parser.MatrixSelector,
+	call *parser.Call,
+) []linter.Diagnostic {
+	ds := []linter.Diagnostic{}
+	pos := node.PositionRange()
+	rng := model.Duration(node.Range)
+
+	if maxRange := r.config.MaxRange; maxRange != 0 && rng > maxRange {
+		msg := fmt.Sprintf("the range `%s` exceeds the maximum range `%s`", rng, maxRange)
+		ds = append(ds, linter.WarningDiagnostic(pos, msg, r.color).WithCode(codes.RangeTooLong))
+	}
+
+	scrapeInterval, job := r.scrapeIntervalOf(node)
+	minSamples := 1.0
+	funcName := ""
+	if call != nil {
+		funcName = call.Func.Name
+		if _, ok := rateFunctions[funcName]; ok {
+			minSamples = r.config.MinRateRangeFactor
+		}
+		if _, ok := instantRateFunctions[funcName]; ok {
+			minSamples = 2
+		}
+	}
+
+	minRange := model.Duration(float64(scrapeInterval) * minSamples)
+	if rng < minRange {
+		target := "the range"
+		if funcName != "" {
+			target = fmt.Sprintf("`%s` over the range", funcName)
+		}
+
+		msg := fmt.Sprintf(
+			"%s `%s` should be at least `%s` (%g x the scrape interval of %s)",
+			target, rng, minRange, minSamples, job,
+		)
+		ds = append(ds, linter.WarningDiagnostic(pos, msg, r.color).WithCode(codes.RangeTooShort))
+	}
+
+	if _, ok := instantRateFunctions[funcName]; ok {
+		if maxRange := r.config.MaxIrateRange; maxRange != 0 && rng > maxRange {
+			msg := fmt.Sprintf(
+				"`%s` only uses the last two samples; the range `%s` longer than `%s` has no effect",
+				funcName, rng, maxRange,
+			)
+			ds = append(ds, linter.WarningDiagnostic(pos, msg, r.color).WithCode(codes.InstantRateRangeTooLong))
+		}
+	}
+
+	return ds
+}
+
+// checkSubquery checks the range/step of a subquery.
+func (r *rangeDuration) checkSubquery(node *parser.SubqueryExpr) []linter.Diagnostic {
+	ds := []linter.Diagnostic{}
+	pos := node.PositionRange()
+	rng := model.Duration(node.Range)
+
+	if maxRange := r.config.MaxRange; maxRange != 0 && rng > maxRange {
+		msg := fmt.Sprintf("the subquery range `%s` exceeds the maximum range `%s`", rng, maxRange)
+		ds = append(ds, linter.WarningDiagnostic(pos, msg, r.color).WithCode(codes.RangeTooLong))
+	}
+
+	if node.Step != 0 && node.Range%node.Step != 0 {
+		msg := fmt.Sprintf(
+			"the subquery step `%s` is not a divisor of the range `%s`",
+			model.Duration(node.Step), rng,
+		)
+		ds = append(ds, linter.WarningDiagnostic(pos, msg, r.color).WithCode(codes.SubqueryStepNotDivisor))
+	}
+
+	return ds
+}
+
+// scrapeIntervalOf determines the scrape interval of the selected series.
+// it also returns the description of the job for the reporting message.
+func (r *rangeDuration) scrapeIntervalOf(node *parser.MatrixSelector) (time.Duration, string) {
+	vs, ok := node.VectorSelector.(*parser.VectorSelector)
+	if !ok {
+		return time.Duration(r.config.ScrapeInterval), "the targets"
+	}
+
+	for _, lm := range vs.LabelMatchers {
+		if lm.Name != jobLabelName || lm.Type != labels.MatchEqual {
+			continue
+		}
+
+		for _, j := range r.jobs {
+			if j.job.MatchString(lm.Value) {
+				return j.scrapeInterval, fmt.Sprintf("the job `%s`", lm.Value)
+			}
+		}
+	}
+
+	return time.Duration(r.config.ScrapeInterval), "the targets"
+}
+
+// Name implements linter.PromQLinterPlugin
+func (*rangeDuration) Name() string {
+	return "range-duration"
+}
+
+// NewRangeDurationPlugin creates a range-duration plugin.
+// the invalid job pattern is returned as the error of Execute(); see RangeDurationConfig.Validate().
+func NewRangeDurationPlugin(
+	config RangeDurationConfig,
+	color linter.PromQLinterColorMode,
+) linter.PromQLinterPlugin {
+	jobs, err := compileJobScrapeIntervals(config.JobScrapeIntervals)
+	return &rangeDuration{config: config, jobs: jobs, err: err, color: color}
+}
+
+// Validate checks the job patterns of JobScrapeIntervals.
+func (c RangeDurationConfig) Validate() error {
+	_, err := compileJobScrapeIntervals(c.JobScrapeIntervals)
+	return err
+}
+
+// compileJobScrapeIntervals compiles the job patterns that match the whole job label.
+func compileJobScrapeIntervals(intervals []JobScrapeInterval) ([]jobScrapeInterval, error) {
+	jobs := make([]jobScrapeInterval, 0, len(intervals))
+	for _, j := range intervals {
+		exp, err := regexp.Compile("^(?:" + j.Job + ")$")
+		if err != nil {
+			return nil, fmt.Errorf("the job pattern `%s` is invalid: %w", j.Job, err)
+		}
+
+		jobs = append(jobs, jobScrapeInterval{exp, time.Duration(j.ScrapeInterval)})
+	}
+
+	return jobs, nil
+}
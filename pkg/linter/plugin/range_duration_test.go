@@ -0,0 +1,113 @@
+/*
+MIT License
+
+# Copyright (c) 2022 Drumato
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package plugin_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Drumato/promqlinter/pkg/codes"
+	"github.com/Drumato/promqlinter/pkg/linter"
+	"github.com/Drumato/promqlinter/pkg/linter/plugin"
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRangeDuration(t *testing.T) {
+	config := plugin.DefaultConfig().RangeDuration
+	config.MaxRange = model.Duration(24 * time.Hour)
+	config.JobScrapeIntervals = []plugin.JobScrapeInterval{
+		{Job: "node.*", ScrapeInterval: model.Duration(15 * time.Second)},
+	}
+
+	tests := []struct {
+		expr     string
+		expected []string
+	}{
+		{`rate(http_requests_total[5m])`, nil},
+		{`rate(node_cpu_seconds_total{job="node"}[1m])`, nil},
+		{`max_over_time(up[1m])`, nil},
+		{`rate(http_requests_total[2m])`, []string{"[WARN]", "`rate` over the range `2m` should be at least `4m` (4 x the scrape interval of the targets)"}},
+		{`rate(node_cpu_seconds_total{job="node"}[30s])`, []string{"[WARN]", "should be at least `1m` (4 x the scrape interval of the job `node`)"}},
+		{`max_over_time(up[30s])`, []string{"[WARN]", "the range `30s` should be at least `1m`"}},
+		{`avg_over_time(up[2d])`, []string{"[WARN]", "exceeds the maximum range `1d`"}},
+		{`max_over_time(rate(up[5m])[2d:5m])`, []string{"[WARN]", "the subquery range `2d` exceeds"}},
+		{`max_over_time(rate(up[5m])[1h:7m])`, []string{"[WARN]", "the subquery step `7m` is not a divisor of the range `1h`"}},
+		{`irate(http_requests_total[1h])`, []string{"[WARN]", "`irate` only uses the last two samples"}},
+	}
+
+	for _, tt := range tests {
+		p := plugin.NewRangeDurationPlugin(config, linter.PromQLinterColorModeDisable)
+		out := pluginTest(t, tt.expr, p)
+
+		if tt.expected == nil {
+			assert.Empty(t, out, tt.expr)
+		}
+		for _, s := range tt.expected {
+			assert.Contains(t, out, s, tt.expr)
+		}
+	}
+}
+
+func TestRangeDurationInvalidJobPattern(t *testing.T) {
+	config := plugin.DefaultConfig()
+	config.RangeDuration.JobScrapeIntervals = []plugin.JobScrapeInterval{
+		{Job: "node(", ScrapeInterval: model.Duration(15 * time.Second)},
+	}
+	assert.Error(t, config.RangeDuration.Validate())
+	assert.Error(t, config.Validate())
+
+	p := plugin.NewRangeDurationPlugin(config.RangeDuration, linter.PromQLinterColorModeDisable)
+	_, err := p.Execute(nil)
+	assert.Error(t, err)
+}
+
+func TestRangeDurationDiagnostics(t *testing.T) {
+	config := plugin.DefaultConfig().RangeDuration
+	config.MaxRange = model.Duration(24 * time.Hour)
+
+	tests := []struct {
+		expr     string
+		expected []reported
+	}{
+		// the boundaries of the ranges are allowed.
+		{`rate(x[4m])`, []reported{}},
+		{`avg_over_time(up[1d])`, []reported{}},
+		{`irate(x[5m])`, []reported{}},
+		{`rate(x[3m59s])`, []reported{{codes.RangeTooShort, linter.DiagnosticLevelWarning, `x[3m59s]`}}},
+		{`sum(rate(x[2m])) / sum(rate(y[5m]))`, []reported{{codes.RangeTooShort, linter.DiagnosticLevelWarning, `x[2m]`}}},
+		{`avg_over_time(up[2d])`, []reported{{codes.RangeTooLong, linter.DiagnosticLevelWarning, `up[2d]`}}},
+		{`max_over_time(rate(up[5m])[2d:5m])`, []reported{{codes.RangeTooLong, linter.DiagnosticLevelWarning, `rate(up[5m])[2d:5m]`}}},
+		{`max_over_time(rate(up[5m])[1h:7m])`, []reported{{codes.SubqueryStepNotDivisor, linter.DiagnosticLevelWarning, `rate(up[5m])[1h:7m]`}}},
+		// the subquery without the step uses the evaluation interval.
+		{`max_over_time(rate(up[5m])[1h:])`, []reported{}},
+		{`irate(x[6m])`, []reported{{codes.InstantRateRangeTooLong, linter.DiagnosticLevelWarning, `x[6m]`}}},
+	}
+
+	for _, tt := range tests {
+		p := plugin.NewRangeDurationPlugin(config, linter.PromQLinterColorModeDisable)
+		assert.Equal(t, tt.expected, pluginDiagnostics(t, tt.expr, nil, p), tt.expr)
+	}
+}
@@ -0,0 +1,109 @@
+/*
+MIT License
+
+# Copyright (c) 2022 Drumato
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package plugin
+
+import (
+	"fmt"
+
+	"github.com/Drumato/promqlinter/pkg/codes"
+	"github.com/Drumato/promqlinter/pkg/linter"
+	"github.com/prometheus/prometheus/promql/parser"
+)
+
+type ranking struct {
+	color linter.PromQLinterColorMode
+}
+
+// Execute implements linter.PromQLinterPlugin
+func (r *ranking) Execute(expr parser.Expr) (linter.Diagnostics, error) {
+	return r.ExecuteWithContext(expr, &linter.ExprContext{Kind: linter.ExprKindQuery})
+}
+
+// ExecuteWithContext implements linter.PromQLinterContextPlugin
+func (r *ranking) ExecuteWithContext(
+	expr parser.Expr,
+	ctx *linter.ExprContext,
+) (linter.Diagnostics, error) {
+	ds := linter.NewDiagnostics()
+	if ctx.Kind == linter.ExprKindQuery {
+		// topk/bottomk and sort are useful for ad-hoc queries.
+		return ds, nil
+	}
+
+	parser.Inspect(expr, func(n parser.Node, path []parser.Node) error {
+		switch node := n.(type) {
+		case *parser.AggregateExpr:
+			if node.Op != parser.TOPK && node.Op != parser.BOTTOMK {
+				return nil
+			}
+
+			var msg string
+			if ctx.Kind == linter.ExprKindAlertingRule {
+				msg = fmt.Sprintf(
+					"`%s` in an alerting rule causes flapping as series enter and leave the set",
+					node.Op,
+				)
+			} else {
+				msg = fmt.Sprintf(
+					"`%s` in a recording rule causes series churn as series enter and leave the set",
+					node.Op,
+				)
+			}
+			ds.Add(linter.WarningDiagnostic(node.PosRange, msg, r.color).WithCode(codes.RankingInRule))
+
+			return nil
+		case *parser.Call:
+			if node.Func.Name != "sort" && node.Func.Name != "sort_desc" {
+				return nil
+			}
+
+			msg := fmt.Sprintf(
+				"`%s` has no effect in %s rules; remove it",
+				node.Func.Name, ctx.Kind,
+			)
+			fix := linter.NewReplaceFix(fmt.Sprintf("remove `%s`", node.Func.Name), node.PosRange, node.Args[0].String())
+			ds.Add(linter.WarningDiagnostic(node.PosRange, msg, r.color).WithCode(codes.SortInRule).WithSuggestedFix(fix))
+
+			return nil
+		default:
+			// traverse all the non-nil children.
+			return nil
+		}
+	})
+
+	return ds, nil
+}
+
+// Name implements linter.PromQLinterPlugin
+func (*ranking) Name() string {
+	return "ranking"
+}
+
+// NewRankingPlugin creates a ranking plugin.
+func NewRankingPlugin(
+	color linter.PromQLinterColorMode,
+) linter.PromQLinterContextPlugin {
+	return &ranking{color}
+}
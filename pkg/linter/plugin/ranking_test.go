@@ -0,0 +1,92 @@
+/*
+MIT License
+
+# Copyright (c) 2022 Drumato
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package plugin_test
+
+import (
+	"testing"
+
+	"github.com/Drumato/promqlinter/pkg/codes"
+	"github.com/Drumato/promqlinter/pkg/linter"
+	"github.com/Drumato/promqlinter/pkg/linter/plugin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRanking(t *testing.T) {
+	alerting := &linter.ExprContext{Kind: linter.ExprKindAlertingRule}
+	recording := &linter.ExprContext{Kind: linter.ExprKindRecordingRule}
+	query := &linter.ExprContext{Kind: linter.ExprKindQuery}
+
+	tests := []struct {
+		expr     string
+		ctx      *linter.ExprContext
+		expected []string
+	}{
+		{`topk(5, rate(http_requests_total[5m])) > 10`, query, nil},
+		{`sort_desc(sum by (job) (up))`, query, nil},
+		{`sum by (job) (up) > 0`, alerting, nil},
+		{`topk(5, rate(http_requests_total[5m])) > 10`, alerting, []string{"[WARN]", "`topk` in an alerting rule causes flapping"}},
+		{`bottomk(3, up)`, recording, []string{"[WARN]", "`bottomk` in a recording rule causes series churn"}},
+		{`sort(sum by (job) (up))`, recording, []string{"[WARN]", "`sort` has no effect in recording rules"}},
+		{`sort_desc(up) == 0`, alerting, []string{"[WARN]", "`sort_desc` has no effect in alerting rules"}},
+	}
+
+	for _, tt := range tests {
+		p := plugin.NewRankingPlugin(linter.PromQLinterColorModeDisable)
+		out := pluginTestWithContext(t, tt.expr, tt.ctx, p)
+
+		if tt.expected == nil {
+			assert.Empty(t, out, tt.expr)
+		}
+		for _, s := range tt.expected {
+			assert.Contains(t, out, s, tt.expr)
+		}
+	}
+}
+
+func TestRankingDiagnostics(t *testing.T) {
+	alerting := &linter.ExprContext{Kind: linter.ExprKindAlertingRule}
+	recording := &linter.ExprContext{Kind: linter.ExprKindRecordingRule}
+
+	tests := []struct {
+		expr     string
+		ctx      *linter.ExprContext
+		expected []reported
+	}{
+		{`topk(5, rate(x[5m])) > 10`, alerting, []reported{{codes.RankingInRule, linter.DiagnosticLevelWarning, `topk(5, rate(x[5m]))`}}},
+		{`topk by (job) (1, x) > 0`, alerting, []reported{{codes.RankingInRule, linter.DiagnosticLevelWarning, `topk by (job) (1, x)`}}},
+		// the closing parenthesis of the enclosing aggregation is not pointed.
+		{`count(topk(5, x)) > 1`, alerting, []reported{{codes.RankingInRule, linter.DiagnosticLevelWarning, `topk(5, x)`}}},
+		{`sum(topk(1, x))`, recording, []reported{{codes.RankingInRule, linter.DiagnosticLevelWarning, `topk(1, x)`}}},
+		{`sort_desc(up) == 0`, alerting, []reported{{codes.SortInRule, linter.DiagnosticLevelWarning, `sort_desc(up)`}}},
+		{`sort(sum by (job) (up))`, recording, []reported{{codes.SortInRule, linter.DiagnosticLevelWarning, `sort(sum by (job) (up))`}}},
+		{`max by (job) (up)`, recording, []reported{}},
+		{`bottomk(3, up)`, &linter.ExprContext{Kind: linter.ExprKindQuery}, []reported{}},
+	}
+
+	for _, tt := range tests {
+		p := plugin.NewRankingPlugin(linter.PromQLinterColorModeDisable)
+		assert.Equal(t, tt.expected, pluginDiagnostics(t, tt.expr, tt.ctx, p), tt.expr)
+	}
+}
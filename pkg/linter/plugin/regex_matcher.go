@@ -0,0 +1,504 @@
+/*
+MIT License
+
+# Copyright (c) 2022 Drumato
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package plugin
+
+import (
+	"fmt"
+	"regexp"
+	"regexp/syntax"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/Drumato/promqlinter/pkg/codes"
+	"github.com/Drumato/promqlinter/pkg/linter"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/promql/parser"
+)
+
+type regexMatcher struct {
+	color linter.PromQLinterColorMode
+}
+
+// Execute implements linter.PromQLinterPlugin
+func (r *regexMatcher) Execute(expr parser.Expr) (linter.Diagnostics, error) {
+	ds := linter.NewDiagnostics()
+	parser.Inspect(expr, func(n parser.Node, path []parser.Node) error {
+		switch node := n.(type) {
+		case *parser.VectorSelector:
+			for _, lm := range node.LabelMatchers {
+				if lm.Type != labels.MatchRegexp && lm.Type != labels.MatchNotRegexp {
+					continue
+				}
+
+				for _, d := range r.checkMatcher(node, path, lm) {
+					ds.Add(d)
+				}
+			}
+
+			return nil
+		default:
+			// traverse all the non-nil children.
+			return nil
+		}
+	})
+
+	return ds, nil
+}
+
+// checkMatcher reports the quality issues of a regex matcher.
+func (r *regexMatcher) checkMatcher(
+	node *parser.VectorSelector,
+	path []parser.Node,
+	lm *labels.Matcher,
+) []linter.Diagnostic {
+	ds := []linter.Diagnostic{}
+
+	re, err := syntax.Parse(lm.Value, syntax.Perl)
+	if err != nil {
+		// the parser has already reported the invalid regex.
+		return ds
+	}
+
+	foldCase := re.Flags&syntax.FoldCase != 0 || strings.Contains(lm.Value, "(?i")
+	inner, anchored := trimRegexAnchors(re)
+	if !foldCase {
+		// the literal diagnostics also drop the anchors, so they are reported instead of the redundant anchors.
+		if alts, ok := literalAlternatives(inner); ok {
+			return r.checkLiteralAlternatives(node, path, lm, alts, anchored || hasRegexCapture(inner))
+		}
+	}
+
+	if anchored {
+		pattern := regexString(inner)
+		msg := fmt.Sprintf(
+			"`%s` has redundant anchors since Prometheus anchors regexes automatically; use `%s` instead",
+			lm, rewriteMatcher(lm, lm.Type, pattern),
+		)
+		fix := rewriteMatcherFix(node, path, lm, lm.Type, pattern)
+		ds = append(ds, linter.InfoDiagnostic(node.PosRange, msg, r.color).WithCode(codes.RedundantRegexAnchors).WithSuggestedFix(fix))
+	}
+
+	if foldCase {
+		msg := fmt.Sprintf("`%s` is case-insensitive, which disables the literal prefix optimizations", lm)
+		ds = append(ds, linter.InfoDiagnostic(node.PosRange, msg, r.color).WithCode(codes.CaseInsensitiveRegex))
+	}
+
+	re = unwrapRegexGroups(inner.Simplify())
+	if re.Op != syntax.OpConcat {
+		return ds
+	}
+
+	subs := re.Sub
+	if len(subs) > 0 && subs[0].Op == syntax.OpBeginText {
+		subs = subs[1:]
+	}
+	if len(subs) > 0 && subs[len(subs)-1].Op == syntax.OpEndText {
+		subs = subs[:len(subs)-1]
+	}
+	if len(subs) < 2 {
+		return ds
+	}
+
+	if isAnyCharStar(subs[0]) {
+		msg := fmt.Sprintf("`%s` starts with `.*`, which forces a scan over all label values", lm)
+		ds = append(ds, linter.InfoDiagnostic(node.PosRange, msg, r.color).WithCode(codes.LeadingWildcardRegex))
+	}
+	if isAnyCharStar(subs[len(subs)-1]) && !isAnyCharStar(subs[0]) {
+		msg := fmt.Sprintf("`%s` ends with `.*`; make sure a prefix match is really intended", lm)
+		ds = append(ds, linter.InfoDiagnostic(node.PosRange, msg, r.color).WithCode(codes.TrailingWildcardRegex))
+	}
+
+	return ds
+}
+
+// checkLiteralAlternatives reports a regex that consists of literal alternatives only.
+// redundant means that the regex has the anchors or the groups to be removed.
+func (r *regexMatcher) checkLiteralAlternatives(
+	node *parser.VectorSelector,
+	path []parser.Node,
+	lm *labels.Matcher,
+	alts []string,
+	redundant bool,
+) []linter.Diagnostic {
+	ds := []linter.Diagnostic{}
+
+	exact := map[string]bool{}
+	folded := map[string]string{}
+	values := []string{}
+	for _, alt := range alts {
+		if exact[alt] {
+			continue
+		}
+		exact[alt] = true
+
+		if prev, ok := folded[strings.ToLower(alt)]; ok {
+			msg := fmt.Sprintf("`%s` has alternatives `%s` and `%s` that differ only by case", lm, prev, alt)
+			ds = append(ds, linter.WarningDiagnostic(node.PosRange, msg, r.color).WithCode(codes.CaseOnlyAlternatives))
+		} else {
+			folded[strings.ToLower(alt)] = alt
+		}
+
+		values = append(values, alt)
+	}
+
+	if len(values) == 1 {
+		typ := labels.MatchEqual
+		if lm.Type == labels.MatchNotRegexp {
+			typ = labels.MatchNotEqual
+		}
+
+		msg := fmt.Sprintf("`%s` is a literal; use `%s` instead", lm, rewriteMatcher(lm, typ, values[0]))
+		fix := rewriteMatcherFix(node, path, lm, typ, values[0])
+		return append(ds, linter.WarningDiagnostic(node.PosRange, msg, r.color).WithCode(codes.LiteralRegex).WithSuggestedFix(fix))
+	}
+
+	quoted := make([]string, 0, len(values))
+	for _, v := range values {
+		quoted = append(quoted, regexp.QuoteMeta(v))
+	}
+
+	set := strings.Join(quoted, "|")
+	if redundant || len(values) < len(alts) {
+		msg := fmt.Sprintf(
+			"`%s` is a set of literals; use `%s` so that each value is looked up directly",
+			lm, rewriteMatcher(lm, lm.Type, set),
+		)
+		fix := rewriteMatcherFix(node, path, lm, lm.Type, set)
+		ds = append(ds, linter.InfoDiagnostic(node.PosRange, msg, r.color).WithCode(codes.LiteralSetRegex).WithSuggestedFix(fix))
+	}
+
+	return ds
+}
+
+// Name implements linter.PromQLinterPlugin
+func (*regexMatcher) Name() string {
+	return "regex-matchers"
+}
+
+// NewRegexMatcherPlugin creates a regex-matchers plugin.
+func NewRegexMatcherPlugin(
+	color linter.PromQLinterColorMode,
+) linter.PromQLinterPlugin {
+	return &regexMatcher{color}
+}
+
+// rewriteMatcher renders the given matcher with the new type/value.
+func rewriteMatcher(lm *labels.Matcher, typ labels.MatchType, value string) string {
+	return fmt.Sprintf("%s%s%q", lm.Name, typ, value)
+}
+
+// rewriteMatcherFix suggests rewriting the matcher with the new type/value.
+func rewriteMatcherFix(
+	node *parser.VectorSelector,
+	path []parser.Node,
+	lm *labels.Matcher,
+	typ labels.MatchType,
+	value string,
+) *linter.SuggestedFix {
+	replacement, err := labels.NewMatcher(typ, lm.Name, value)
+	if err != nil {
+		return nil
+	}
+
+	description := fmt.Sprintf("replace `%s` with `%s`", lm, replacement)
+	return replaceMatcherFix(description, node, path, lm, replacement)
+}
+
+// maxLiteralAlternatives is the maximum number of the literals that a regex is expanded into.
+const maxLiteralAlternatives = 100
+
+// trimRegexAnchors removes the leading `^` and the trailing `$` from the regex.
+func trimRegexAnchors(re *syntax.Regexp) (*syntax.Regexp, bool) {
+	if re.Op != syntax.OpConcat {
+		return re, false
+	}
+
+	subs := re.Sub
+	anchored := false
+	if len(subs) > 0 && subs[0].Op == syntax.OpBeginText {
+		subs = subs[1:]
+		anchored = true
+	}
+	if len(subs) > 0 && subs[len(subs)-1].Op == syntax.OpEndText {
+		subs = subs[:len(subs)-1]
+		anchored = true
+	}
+
+	switch {
+	case !anchored:
+		return re, false
+	case len(subs) == 0:
+		return &syntax.Regexp{Op: syntax.OpEmptyMatch, Flags: re.Flags}, true
+	case len(subs) == 1:
+		return subs[0], true
+	default:
+		return &syntax.Regexp{Op: syntax.OpConcat, Flags: re.Flags, Sub: subs}, true
+	}
+}
+
+// regexString renders the regex in the Perl syntax.
+// unlike (*syntax.Regexp).String(), it doesn't add the flag groups like `(?-s:...)` to the whole regex.
+func regexString(re *syntax.Regexp) string {
+	b := &strings.Builder{}
+	writeRegex(b, re)
+	return b.String()
+}
+
+// writeRegex writes the regex to the builder; see regexString().
+func writeRegex(b *strings.Builder, re *syntax.Regexp) {
+	switch re.Op {
+	case syntax.OpEmptyMatch:
+	case syntax.OpLiteral:
+		if re.Flags&syntax.FoldCase != 0 {
+			b.WriteString("(?i:" + regexp.QuoteMeta(string(re.Rune)) + ")")
+		} else {
+			b.WriteString(regexp.QuoteMeta(string(re.Rune)))
+		}
+	case syntax.OpCharClass:
+		writeRegexCharClass(b, re.Rune)
+	case syntax.OpAnyCharNotNL:
+		b.WriteString(".")
+	case syntax.OpAnyChar:
+		b.WriteString("(?s:.)")
+	case syntax.OpBeginText:
+		b.WriteString(`\A`)
+	case syntax.OpEndText:
+		b.WriteString(`\z`)
+	case syntax.OpBeginLine:
+		b.WriteString("(?m:^)")
+	case syntax.OpEndLine:
+		b.WriteString("(?m:$)")
+	case syntax.OpWordBoundary:
+		b.WriteString(`\b`)
+	case syntax.OpNoWordBoundary:
+		b.WriteString(`\B`)
+	case syntax.OpCapture:
+		if re.Name != "" {
+			b.WriteString("(?P<" + re.Name + ">")
+		} else {
+			b.WriteString("(")
+		}
+		writeRegex(b, re.Sub[0])
+		b.WriteString(")")
+	case syntax.OpStar, syntax.OpPlus, syntax.OpQuest, syntax.OpRepeat:
+		sub := re.Sub[0]
+		if sub.Op >= syntax.OpStar || sub.Op == syntax.OpEmptyMatch ||
+			(sub.Op == syntax.OpLiteral && len(sub.Rune) > 1 && sub.Flags&syntax.FoldCase == 0) {
+			b.WriteString("(?:")
+			writeRegex(b, sub)
+			b.WriteString(")")
+		} else {
+			writeRegex(b, sub)
+		}
+
+		switch re.Op {
+		case syntax.OpStar:
+			b.WriteString("*")
+		case syntax.OpPlus:
+			b.WriteString("+")
+		case syntax.OpQuest:
+			b.WriteString("?")
+		default:
+			b.WriteString("{" + strconv.Itoa(re.Min))
+			if re.Max != re.Min {
+				b.WriteString(",")
+				if re.Max >= 0 {
+					b.WriteString(strconv.Itoa(re.Max))
+				}
+			}
+			b.WriteString("}")
+		}
+		if re.Flags&syntax.NonGreedy != 0 {
+			b.WriteString("?")
+		}
+	case syntax.OpConcat:
+		for _, sub := range re.Sub {
+			if sub.Op == syntax.OpAlternate {
+				b.WriteString("(?:")
+				writeRegex(b, sub)
+				b.WriteString(")")
+			} else {
+				writeRegex(b, sub)
+			}
+		}
+	case syntax.OpAlternate:
+		for i, sub := range re.Sub {
+			if i != 0 {
+				b.WriteString("|")
+			}
+			writeRegex(b, sub)
+		}
+	default:
+		b.WriteString(re.String())
+	}
+}
+
+// writeRegexCharClass writes the character class of the given rune ranges.
+func writeRegexCharClass(b *strings.Builder, ranges []rune) {
+	b.WriteString("[")
+	if len(ranges) == 0 || (ranges[0] == 0 && ranges[len(ranges)-1] == unicode.MaxRune) {
+		// the negated class is more readable.
+		b.WriteString("^")
+		ranges = complementRuneRanges(ranges)
+	}
+
+	for i := 0; i+1 < len(ranges); i += 2 {
+		lo, hi := ranges[i], ranges[i+1]
+		writeRegexClassRune(b, lo)
+		if hi > lo+1 {
+			b.WriteString("-")
+		}
+		if hi > lo {
+			writeRegexClassRune(b, hi)
+		}
+	}
+	b.WriteString("]")
+}
+
+// writeRegexClassRune writes the rune in a character class with escaping.
+func writeRegexClassRune(b *strings.Builder, r rune) {
+	switch {
+	case strings.ContainsRune(`\-[]^`, r):
+		b.WriteString(`\` + string(r))
+	case unicode.IsPrint(r):
+		b.WriteRune(r)
+	default:
+		b.WriteString(fmt.Sprintf(`\x{%x}`, r))
+	}
+}
+
+// complementRuneRanges returns the ranges of the runes that the given ranges don't contain.
+func complementRuneRanges(ranges []rune) []rune {
+	complement := []rune{}
+	next := rune(0)
+	for i := 0; i+1 < len(ranges); i += 2 {
+		if ranges[i] > next {
+			complement = append(complement, next, ranges[i]-1)
+		}
+		next = ranges[i+1] + 1
+	}
+	if next <= unicode.MaxRune {
+		complement = append(complement, next, unicode.MaxRune)
+	}
+
+	return complement
+}
+
+// literalAlternatives expands the regex like `(foo|bar)` into the literals.
+// it returns false if the regex matches anything other than a small set of literals.
+func literalAlternatives(re *syntax.Regexp) ([]string, bool) {
+	switch re.Op {
+	case syntax.OpEmptyMatch:
+		return []string{""}, true
+	case syntax.OpLiteral:
+		if re.Flags&syntax.FoldCase != 0 {
+			return nil, false
+		}
+
+		return []string{string(re.Rune)}, true
+	case syntax.OpCharClass:
+		// the parser factors the alternatives like `foo|foz` into `fo[oz]`.
+		alts := []string{}
+		for i := 0; i+1 < len(re.Rune); i += 2 {
+			if len(alts)+int(re.Rune[i+1]-re.Rune[i]) >= maxLiteralAlternatives {
+				return nil, false
+			}
+
+			for c := re.Rune[i]; c <= re.Rune[i+1]; c++ {
+				alts = append(alts, string(c))
+			}
+		}
+
+		return alts, true
+	case syntax.OpCapture:
+		return literalAlternatives(re.Sub[0])
+	case syntax.OpAlternate:
+		alts := []string{}
+		for _, sub := range re.Sub {
+			subAlts, ok := literalAlternatives(sub)
+			if !ok || len(alts)+len(subAlts) > maxLiteralAlternatives {
+				return nil, false
+			}
+			alts = append(alts, subAlts...)
+		}
+
+		return alts, true
+	case syntax.OpConcat:
+		alts := []string{""}
+		for _, sub := range re.Sub {
+			subAlts, ok := literalAlternatives(sub)
+			if !ok || len(alts)*len(subAlts) > maxLiteralAlternatives {
+				return nil, false
+			}
+
+			next := make([]string, 0, len(alts)*len(subAlts))
+			for _, prefix := range alts {
+				for _, alt := range subAlts {
+					next = append(next, prefix+alt)
+				}
+			}
+			alts = next
+		}
+
+		return alts, true
+	default:
+		return nil, false
+	}
+}
+
+// hasRegexCapture determines whether the regex contains a capturing group.
+func hasRegexCapture(re *syntax.Regexp) bool {
+	if re.Op == syntax.OpCapture {
+		return true
+	}
+
+	for _, sub := range re.Sub {
+		if hasRegexCapture(sub) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// unwrapRegexGroups removes the capturing groups that enclose the whole regex.
+func unwrapRegexGroups(re *syntax.Regexp) *syntax.Regexp {
+	for re.Op == syntax.OpCapture {
+		re = re.Sub[0]
+	}
+
+	return re
+}
+
+// isAnyCharStar determines whether the regex is `.*`.
+func isAnyCharStar(re *syntax.Regexp) bool {
+	if re.Op != syntax.OpStar {
+		return false
+	}
+
+	sub := re.Sub[0]
+	return sub.Op == syntax.OpAnyChar || sub.Op == syntax.OpAnyCharNotNL
+}
@@ -0,0 +1,110 @@
+/*
+MIT License
+
+# Copyright (c) 2022 Drumato
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package plugin_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/Drumato/promqlinter/pkg/codes"
+	"github.com/Drumato/promqlinter/pkg/linter"
+	"github.com/Drumato/promqlinter/pkg/linter/plugin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRegexMatchers(t *testing.T) {
+	tests := []struct {
+		expr     string
+		expected []string
+	}{
+		{`up{job=~"foo|bar"}`, nil},
+		{`up{job=~"api-.+"}`, nil},
+		{`up{job=~"foo"}`, []string{"[WARN]", "use `job=\"foo\"` instead"}},
+		{`up{job!~"foo\\.bar"}`, []string{"[WARN]", "use `job!=\"foo.bar\"` instead"}},
+		{`up{job=~"^foo.+$"}`, []string{"[INFO]", "redundant anchors", "use `job=~\"foo.+\"` instead"}},
+		{`up{job=~"^[a-z]+-(api|web)\\d*$"}`, []string{"use `job=~\"[a-z]+-(api|web)[0-9]*\"` instead"}},
+		{`up{job=~"^foo\\$.+"}`, []string{"use `job=~\"foo\\\\$.+\"` instead"}},
+		{`up{job=~"^(?:ab)+[^-]{2,}?$"}`, []string{"use `job=~\"(?:ab)+[^\\\\-]{2,}?\"` instead"}},
+		{`up{job=~"(foo|bar)"}`, []string{"[INFO]", "use `job=~\"foo|bar\"`"}},
+		{`up{job=~"GET|get"}`, []string{"[WARN]", "differ only by case"}},
+		{`up{job=~"(?i)get.+"}`, []string{"[INFO]", "case-insensitive"}},
+		{`up{job=~".*foo"}`, []string{"[INFO]", "starts with `.*`"}},
+		{`up{job=~"foo.*"}`, []string{"[INFO]", "ends with `.*`"}},
+		{`up{job=~"foo|foz"}`, nil},
+		{`up{job=~"^foo|bar"}`, nil},
+		{`up{job=~"^(foo|bar)$"}`, []string{"[INFO]", "use `job=~\"foo|bar\"`"}},
+		{`up{job=~"node|api|node"}`, []string{"[INFO]", "use `job=~\"node|api\"`"}},
+		{`up{job=~"GET|GEt"}`, []string{"[WARN]", "`GET` and `GEt` that differ only by case"}},
+	}
+
+	for _, tt := range tests {
+		p := plugin.NewRegexMatcherPlugin(linter.PromQLinterColorModeDisable)
+		out := pluginTest(t, tt.expr, p)
+
+		if tt.expected == nil {
+			assert.Empty(t, out, tt.expr)
+		}
+		for _, s := range tt.expected {
+			assert.Contains(t, out, s, tt.expr)
+		}
+	}
+}
+
+func TestRegexMatchersAnchoredLiteral(t *testing.T) {
+	p := plugin.NewRegexMatcherPlugin(linter.PromQLinterColorModeDisable)
+	out := pluginTest(t, `up{job=~"^foo$"}`, p)
+
+	assert.Contains(t, out, "use `job=\"foo\"` instead")
+	assert.NotContains(t, out, "redundant anchors")
+	assert.Equal(t, 1, strings.Count(out, "fix: "))
+}
+
+func TestRegexMatchersDiagnostics(t *testing.T) {
+	tests := []struct {
+		expr     string
+		expected []reported
+	}{
+		{`up{job=~"foo"}`, []reported{{codes.LiteralRegex, linter.DiagnosticLevelWarning, `up{job=~"foo"}`}}},
+		{`up{job=~"^foo.+$"}`, []reported{{codes.RedundantRegexAnchors, linter.DiagnosticLevelInfo, `up{job=~"^foo.+$"}`}}},
+		{`up{job=~"(foo|bar)"}`, []reported{{codes.LiteralSetRegex, linter.DiagnosticLevelInfo, `up{job=~"(foo|bar)"}`}}},
+		{`up{job=~"GET|get"}`, []reported{{codes.CaseOnlyAlternatives, linter.DiagnosticLevelWarning, `up{job=~"GET|get"}`}}},
+		{`up{job=~"(?i)get.+"}`, []reported{{codes.CaseInsensitiveRegex, linter.DiagnosticLevelInfo, `up{job=~"(?i)get.+"}`}}},
+		{`up{job=~".*foo"}`, []reported{{codes.LeadingWildcardRegex, linter.DiagnosticLevelInfo, `up{job=~".*foo"}`}}},
+		{`sum(up{env="prod", job=~"foo.*"})`, []reported{{codes.TrailingWildcardRegex, linter.DiagnosticLevelInfo, `up{env="prod", job=~"foo.*"}`}}},
+		// the anchored literal is reported once as a literal.
+		{`up{job=~"^foo$"}`, []reported{{codes.LiteralRegex, linter.DiagnosticLevelWarning, `up{job=~"^foo$"}`}}},
+		// the escaped dot is a literal, not a wildcard.
+		{`up{job=~"foo\\.*"}`, []reported{}},
+		// the anchor applies to the first alternative only.
+		{`up{job=~"^foo|bar"}`, []reported{}},
+		// `.*` alone is left to the label-matchers plugin.
+		{`up{job=~".*"}`, []reported{}},
+	}
+
+	for _, tt := range tests {
+		p := plugin.NewRegexMatcherPlugin(linter.PromQLinterColorModeDisable)
+		assert.Equal(t, tt.expected, pluginDiagnostics(t, tt.expr, nil, p), tt.expr)
+	}
+}
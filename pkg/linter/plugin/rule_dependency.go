@@ -0,0 +1,122 @@
+/*
+MIT License
+
+# Copyright (c) 2022 Drumato
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package plugin
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Drumato/promqlinter/pkg/codes"
+	"github.com/Drumato/promqlinter/pkg/linter"
+	"github.com/Drumato/promqlinter/pkg/rulegraph"
+	"github.com/prometheus/prometheus/promql/parser"
+)
+
+type ruleDependency struct {
+	graph *rulegraph.Graph
+	color linter.PromQLinterColorMode
+}
+
+// Execute implements linter.PromQLinterPlugin
+func (r *ruleDependency) Execute(expr parser.Expr) (linter.Diagnostics, error) {
+	return r.ExecuteWithContext(expr, &linter.ExprContext{Kind: linter.ExprKindQuery})
+}
+
+// ExecuteWithContext implements linter.PromQLinterContextPlugin
+func (r *ruleDependency) ExecuteWithContext(
+	expr parser.Expr,
+	ctx *linter.ExprContext,
+) (linter.Diagnostics, error) {
+	ds := linter.NewDiagnostics()
+
+	current, ok := r.graph.RuleOf(ctx)
+	if !ok {
+		// the expression doesn't come from the scanned manifests.
+		return ds, nil
+	}
+
+	if cycle := r.graph.Cycle(current); cycle != nil {
+		names := make([]string, 0, len(cycle))
+		for _, c := range cycle {
+			names = append(names, fmt.Sprintf("`%s` (%s)", c.Record, c.Location()))
+		}
+
+		msg := fmt.Sprintf(
+			"the recording rule `%s` depends on itself: %s",
+			current.Record, strings.Join(names, " -> "),
+		)
+		ds.Add(linter.ErrorDiagnostic(expr.PositionRange(), msg, r.color).WithCode(codes.RecordingRuleCycle))
+	}
+
+	parser.Inspect(expr, func(n parser.Node, path []parser.Node) error {
+		switch node := n.(type) {
+		case *parser.VectorSelector:
+			name := rulegraph.SelectorMetricName(node)
+			if name == "" {
+				return nil
+			}
+
+			records := r.graph.Records[name]
+			if len(records) == 0 && rulegraph.IsRecordingRuleName(name) {
+				msg := fmt.Sprintf("`%s` is not recorded by any rule", name)
+				ds.Add(linter.WarningDiagnostic(node.PosRange, msg, r.color).WithCode(codes.UndefinedRecordedMetric))
+			}
+
+			for _, record := range records {
+				if record.File != current.File ||
+					record.GroupIndex != current.GroupIndex ||
+					record.Index <= current.Index {
+					continue
+				}
+
+				msg := fmt.Sprintf(
+					"`%s` is recorded later in the group `%s` (%s); the rule reads the result of the previous evaluation",
+					name, current.GroupName, record.Location(),
+				)
+				ds.Add(linter.WarningDiagnostic(node.PosRange, msg, r.color).WithCode(codes.RecordedLater))
+			}
+
+			return nil
+		default:
+			// traverse all the non-nil children.
+			return nil
+		}
+	})
+
+	return ds, nil
+}
+
+// Name implements linter.PromQLinterPlugin
+func (*ruleDependency) Name() string {
+	return "rule-dependencies"
+}
+
+// NewRuleDependencyPlugin creates a rule-dependencies plugin.
+func NewRuleDependencyPlugin(
+	graph *rulegraph.Graph,
+	color linter.PromQLinterColorMode,
+) linter.PromQLinterContextPlugin {
+	return &ruleDependency{graph, color}
+}
@@ -0,0 +1,116 @@
+/*
+MIT License
+
+# Copyright (c) 2022 Drumato
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package plugin_test
+
+import (
+	"testing"
+
+	"github.com/Drumato/promqlinter/pkg/codes"
+	"github.com/Drumato/promqlinter/pkg/linter"
+	"github.com/Drumato/promqlinter/pkg/linter/plugin"
+	"github.com/Drumato/promqlinter/pkg/manifest"
+	"github.com/Drumato/promqlinter/pkg/rulegraph"
+	"github.com/stretchr/testify/assert"
+)
+
+const ruleDependenciesManifest = `spec:
+  groups:
+  - name: example
+    rules:
+    - alert: HighErrorRatio
+      expr: job:errors:ratio5m > 0.1
+    - record: job:errors:ratio5m
+      expr: job:errors:rate5m / job:requests:rate5m
+    - record: job:errors:rate5m
+      expr: sum by (job) (rate(errors_total[5m]))
+    - record: job:loop:a
+      expr: job:loop:b
+    - record: job:loop:b
+      expr: job:loop:a + 1
+    - record: job:ok:sum
+      expr: sum by (job) (up)
+`
+
+func TestRuleDependencies(t *testing.T) {
+	f, err := manifest.Parse("rules.yaml", []byte(ruleDependenciesManifest))
+	assert.NoError(t, err)
+	g := rulegraph.New(f.Rules)
+
+	tests := []struct {
+		rule     *manifest.Rule
+		expected []string
+	}{
+		{f.Rules[0], []string{"[WARN]", "`job:errors:ratio5m` is recorded later in the group `example` (rules.yaml:7); the rule reads the result of the previous evaluation"}},
+		{f.Rules[1], []string{"`job:errors:rate5m` is recorded later in the group `example` (rules.yaml:9)", "`job:requests:rate5m` is not recorded by any rule"}},
+		{f.Rules[2], nil},
+		{f.Rules[3], []string{"[ERROR]", "the recording rule `job:loop:a` depends on itself: `job:loop:a` (rules.yaml:11) -> `job:loop:b` (rules.yaml:13) -> `job:loop:a` (rules.yaml:11)"}},
+		{f.Rules[5], nil},
+	}
+
+	for _, tt := range tests {
+		p := plugin.NewRuleDependencyPlugin(g, linter.PromQLinterColorModeDisable)
+		out := pluginTestWithContext(t, tt.rule.Expr.StrVal, tt.rule.Context(), p)
+
+		if tt.expected == nil {
+			assert.Empty(t, out, tt.rule.Location())
+		}
+		for _, s := range tt.expected {
+			assert.Contains(t, out, s, tt.rule.Location())
+		}
+	}
+}
+
+func TestRuleDependenciesDiagnostics(t *testing.T) {
+	f, err := manifest.Parse("rules.yaml", []byte(ruleDependenciesManifest))
+	assert.NoError(t, err)
+	g := rulegraph.New(f.Rules)
+
+	tests := []struct {
+		rule     *manifest.Rule
+		expected []reported
+	}{
+		// the selectors of the recorded metrics are pointed.
+		{
+			f.Rules[1],
+			[]reported{
+				{codes.RecordedLater, linter.DiagnosticLevelWarning, `job:errors:rate5m`},
+				{codes.UndefinedRecordedMetric, linter.DiagnosticLevelWarning, `job:requests:rate5m`},
+			},
+		},
+		// the cycle is pointed on the whole expression.
+		{f.Rules[4], []reported{{codes.RecordingRuleCycle, linter.DiagnosticLevelError, `job:loop:a + 1`}}},
+		// the rule that reads no recorded metric has no dependency.
+		{f.Rules[2], []reported{}},
+	}
+
+	for _, tt := range tests {
+		p := plugin.NewRuleDependencyPlugin(g, linter.PromQLinterColorModeDisable)
+		assert.Equal(t, tt.expected, pluginDiagnostics(t, tt.rule.Expr.StrVal, tt.rule.Context(), p), tt.rule.Location())
+	}
+
+	// ad-hoc queries are not checked.
+	p := plugin.NewRuleDependencyPlugin(g, linter.PromQLinterColorModeDisable)
+	assert.Equal(t, []reported{}, pluginDiagnostics(t, `job:requests:rate5m`, nil, p))
+}
@@ -0,0 +1,332 @@
+/*
+MIT License
+
+# Copyright (c) 2022 Drumato
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package plugin
+
+import (
+	"fmt"
+
+	"github.com/Drumato/promqlinter/pkg/codes"
+	"github.com/Drumato/promqlinter/pkg/linter"
+	"github.com/Drumato/promqlinter/pkg/promqlutil"
+	"github.com/prometheus/prometheus/promql/parser"
+)
+
+const (
+	// unaryPrecedence is the precedence of the unary operators.
+	// the parser gives them the same precedence as the multiplication.
+	unaryPrecedence = 5
+)
+
+// nestedAggregationOps are the aggregations that give the same result if they are nested.
+var nestedAggregationOps = map[parser.ItemType]struct{}{
+	parser.SUM:   {},
+	parser.MIN:   {},
+	parser.MAX:   {},
+	parser.GROUP: {},
+}
+
+type simplify struct {
+	color linter.PromQLinterColorMode
+}
+
+// Execute implements linter.PromQLinterPlugin
+func (s *simplify) Execute(expr parser.Expr) (linter.Diagnostics, error) {
+	ds := linter.NewDiagnostics()
+	parser.Inspect(expr, func(n parser.Node, path []parser.Node) error {
+		var (
+			replacement string
+			code        string
+			ok          bool
+		)
+
+		switch node := n.(type) {
+		case *parser.ParenExpr:
+			var parent parser.Node
+			if len(path) != 0 {
+				parent = path[len(path)-1]
+			}
+			replacement, ok = simplifyParenExpr(node, parent)
+			code = codes.RedundantParens
+		case *parser.UnaryExpr:
+			replacement, ok = simplifyUnaryExpr(node)
+			code = codes.DoubleNegation
+		case *parser.AggregateExpr:
+			replacement, ok = simplifyAggregateExpr(node)
+			code = codes.NestedAggregation
+		case *parser.BinaryExpr:
+			replacement, code, ok = simplifyBinaryExpr(node)
+		default:
+			// traverse all the non-nil children.
+			return nil
+		}
+
+		if ok {
+			e := n.(parser.Expr)
+			msg := fmt.Sprintf("`%s` can be simplified; use `%s` instead", e, replacement)
+			fix := linter.NewReplaceFix(fmt.Sprintf("replace with `%s`", replacement), e.PositionRange(), replacement)
+			ds.Add(linter.InfoDiagnostic(e.PositionRange(), msg, s.color).WithCode(code).WithSuggestedFix(fix))
+		}
+
+		return nil
+	})
+
+	return ds, nil
+}
+
+// Name implements linter.PromQLinterPlugin
+func (*simplify) Name() string {
+	return "simplify"
+}
+
+// NewSimplifyPlugin creates a simplify plugin.
+func NewSimplifyPlugin(
+	color linter.PromQLinterColorMode,
+) linter.PromQLinterPlugin {
+	return &simplify{color}
+}
+
+// simplifyParenExpr reports the parentheses that have no effect.
+func simplifyParenExpr(node *parser.ParenExpr, parent parser.Node) (string, bool) {
+	if _, ok := parent.(*parser.ParenExpr); ok {
+		// the outermost parentheses are reported.
+		return "", false
+	}
+
+	inner := unwrapParenExpr(node)
+	if needsParens(inner, node, parent) {
+		return fmt.Sprintf("(%s)", inner), node.Expr != inner
+	}
+
+	return inner.String(), true
+}
+
+// needsParens determines whether the parentheses around inner are needed in the parent.
+// wrapper is the outermost ParenExpr that is the child of the parent.
+func needsParens(inner parser.Expr, wrapper *parser.ParenExpr, parent parser.Node) bool {
+	prec := 0
+	switch e := inner.(type) {
+	case *parser.BinaryExpr:
+		prec = binaryPrecedence(e.Op)
+	case *parser.UnaryExpr:
+		prec = unaryPrecedence
+	case *parser.NumberLiteral:
+		if e.Val >= 0 {
+			return false
+		}
+		prec = unaryPrecedence
+	default:
+		// selectors, calls, aggregations and so on bind the tightest.
+		return false
+	}
+
+	switch p := parent.(type) {
+	case *parser.BinaryExpr:
+		parentPrec := binaryPrecedence(p.Op)
+		if prec == unaryPrecedence {
+			if _, ok := inner.(*parser.BinaryExpr); !ok {
+				return parentPrec >= unaryPrecedence
+			}
+		}
+
+		if prec != parentPrec {
+			return prec < parentPrec
+		}
+
+		if p.Op == parser.POW {
+			// `^` is right-associative.
+			return p.LHS == parser.Expr(wrapper)
+		}
+
+		return p.RHS == parser.Expr(wrapper)
+	case *parser.UnaryExpr, *parser.SubqueryExpr:
+		return true
+	default:
+		return false
+	}
+}
+
+// binaryPrecedence returns the precedence of the binary operator.
+// the larger value binds tighter.
+func binaryPrecedence(op parser.ItemType) int {
+	switch op {
+	case parser.LOR:
+		return 1
+	case parser.LAND, parser.LUNLESS:
+		return 2
+	case parser.EQLC, parser.NEQ, parser.LTE, parser.LSS, parser.GTE, parser.GTR:
+		return 3
+	case parser.ADD, parser.SUB:
+		return 4
+	case parser.MUL, parser.DIV, parser.MOD, parser.ATAN2:
+		return 5
+	case parser.POW:
+		return 6
+	default:
+		// unreachable
+		return 0
+	}
+}
+
+// simplifyUnaryExpr reports the double negation like `-(-x)`.
+func simplifyUnaryExpr(node *parser.UnaryExpr) (string, bool) {
+	if node.Op != parser.SUB {
+		return "", false
+	}
+
+	switch inner := unwrapParenExpr(node.Expr).(type) {
+	case *parser.UnaryExpr:
+		if inner.Op != parser.SUB {
+			return "", false
+		}
+
+		return inner.Expr.String(), true
+	case *parser.NumberLiteral:
+		if inner.Val >= 0 {
+			return "", false
+		}
+
+		return (&parser.NumberLiteral{Val: -inner.Val}).String(), true
+	default:
+		return "", false
+	}
+}
+
+// simplifyAggregateExpr reports the nested aggregations like `sum(sum by (job) (x))`.
+func simplifyAggregateExpr(node *parser.AggregateExpr) (string, bool) {
+	if _, ok := nestedAggregationOps[node.Op]; !ok {
+		return "", false
+	}
+
+	inner, ok := unwrapParenExpr(node.Expr).(*parser.AggregateExpr)
+	if !ok || inner.Op != node.Op {
+		return "", false
+	}
+
+	if len(node.Grouping) != 0 || node.Without {
+		if node.Without {
+			return "", false
+		}
+
+		// the outer grouping labels must be kept by the inner aggregation.
+		for _, name := range node.Grouping {
+			if !keptByGrouping(inner, name) {
+				return "", false
+			}
+		}
+	}
+
+	simplified := &parser.AggregateExpr{
+		Op:       node.Op,
+		Expr:     inner.Expr,
+		Grouping: node.Grouping,
+		Without:  node.Without,
+	}
+
+	return simplified.String(), true
+}
+
+// keptByGrouping determines whether the aggregation keeps the label.
+func keptByGrouping(node *parser.AggregateExpr, name string) bool {
+	for _, l := range node.Grouping {
+		if l == name {
+			return !node.Without
+		}
+	}
+
+	return node.Without
+}
+
+// simplifyBinaryExpr reports `rate(x[5m]) * 300` and `x > a and x < b`.
+// it also returns the code of the check.
+func simplifyBinaryExpr(node *parser.BinaryExpr) (string, string, bool) {
+	switch node.Op {
+	case parser.MUL:
+		if s, ok := simplifyRateMultiplication(node.LHS, node.RHS); ok {
+			return s, codes.RateMultiplication, true
+		}
+
+		s, ok := simplifyRateMultiplication(node.RHS, node.LHS)
+		return s, codes.RateMultiplication, ok
+	case parser.LAND:
+		s, ok := simplifyRangeFilter(node)
+		return s, codes.RangeFilter, ok
+	default:
+		return "", "", false
+	}
+}
+
+// simplifyRateMultiplication reports `rate(x[5m]) * 300` that is the same as `increase(x[5m])`.
+func simplifyRateMultiplication(lhs, rhs parser.Expr) (string, bool) {
+	call, ok := unwrapParenExpr(lhs).(*parser.Call)
+	if !ok || call.Func.Name != "rate" {
+		return "", false
+	}
+
+	nl, ok := unwrapParenExpr(rhs).(*parser.NumberLiteral)
+	if !ok {
+		return "", false
+	}
+
+	ms, ok := call.Args[0].(*parser.MatrixSelector)
+	if !ok || ms.Range.Seconds() != nl.Val {
+		return "", false
+	}
+
+	increase := &parser.Call{Func: parser.Functions["increase"], Args: call.Args}
+
+	return increase.String(), true
+}
+
+// simplifyRangeFilter reports `x > a and x < b` that is the same as `x > a < b`.
+func simplifyRangeFilter(node *parser.BinaryExpr) (string, bool) {
+	vm := node.VectorMatching
+	if vm != nil && (vm.On || len(vm.MatchingLabels) != 0) {
+		return "", false
+	}
+
+	lhs, ok := unwrapParenExpr(node.LHS).(*parser.BinaryExpr)
+	if !ok || !isScalarFilter(lhs) {
+		return "", false
+	}
+
+	rhs, ok := unwrapParenExpr(node.RHS).(*parser.BinaryExpr)
+	if !ok || !isScalarFilter(rhs) {
+		return "", false
+	}
+
+	if !promqlutil.IsSameExpr(lhs.LHS, rhs.LHS) {
+		return "", false
+	}
+
+	return fmt.Sprintf("%s %s %s", lhs, rhs.Op, rhs.RHS), true
+}
+
+// isScalarFilter determines whether the expression is like `x > 0`.
+func isScalarFilter(node *parser.BinaryExpr) bool {
+	return node.Op.IsComparisonOperator() &&
+		!node.ReturnBool &&
+		node.LHS.Type() == parser.ValueTypeVector &&
+		node.RHS.Type() == parser.ValueTypeScalar
+}
@@ -0,0 +1,101 @@
+/*
+MIT License
+
+# Copyright (c) 2022 Drumato
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package plugin_test
+
+import (
+	"testing"
+
+	"github.com/Drumato/promqlinter/pkg/codes"
+	"github.com/Drumato/promqlinter/pkg/linter"
+	"github.com/Drumato/promqlinter/pkg/linter/plugin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSimplify(t *testing.T) {
+	tests := []struct {
+		expr     string
+		expected []string
+	}{
+		{`(a + b) * c`, nil},
+		{`a - (b - c)`, nil},
+		{`(a ^ b) ^ c`, nil},
+		{`-(a + b)`, nil},
+		{`(-2) ^ 2`, nil},
+		{`(a + b)[5m:1m]`, nil},
+		{`sum by (instance) (sum by (job) (up))`, nil},
+		{`count(count by (job) (up))`, nil},
+		{`rate(x[5m]) * 60`, nil},
+		{`x > 0 and y < 10`, nil},
+		{`x > 0 and on (job) x < 10`, nil},
+		{`(sum(up))`, []string{"[INFO] PQL1601", "`(sum(up))` can be simplified; use `sum(up)` instead"}},
+		{`a + (b * c)`, []string{"[INFO]", "use `b * c` instead"}},
+		{`(a - b) - c`, []string{"[INFO]", "use `a - b` instead"}},
+		{`a ^ (b ^ c)`, []string{"[INFO]", "use `b ^ c` instead"}},
+		{`((a + b)) * c`, []string{"[INFO]", "`((a + b))` can be simplified; use `(a + b)` instead"}},
+		{`-(-x)`, []string{"[INFO] PQL1602", "`-(-x)` can be simplified; use `x` instead"}},
+		{`sum(sum by (job) (up))`, []string{"[INFO] PQL1603", "use `sum(up)` instead"}},
+		{`max by (job) (max by (job, instance) (up))`, []string{"[INFO]", "use `max by (job) (up)` instead"}},
+		{`sum by (job) (sum without (instance) (up))`, []string{"[INFO]", "use `sum by (job) (up)` instead"}},
+		{`rate(http_requests_total[5m]) * 300`, []string{"[INFO] PQL1604", "use `increase(http_requests_total[5m])` instead"}},
+		{`up > 0 and up < 10`, []string{"[INFO] PQL1605", "`up > 0 and up < 10` can be simplified; use `up > 0 < 10` instead"}},
+	}
+
+	for _, tt := range tests {
+		p := plugin.NewSimplifyPlugin(linter.PromQLinterColorModeDisable)
+		out := pluginTest(t, tt.expr, p)
+
+		if tt.expected == nil {
+			assert.Empty(t, out, tt.expr)
+		}
+		for _, s := range tt.expected {
+			assert.Contains(t, out, s, tt.expr)
+		}
+	}
+}
+
+func TestSimplifyDiagnostics(t *testing.T) {
+	tests := []struct {
+		expr     string
+		expected []reported
+	}{
+		{`abs((x))`, []reported{{codes.RedundantParens, linter.DiagnosticLevelInfo, `(x)`}}},
+		{`((a + b)) * c`, []reported{{codes.RedundantParens, linter.DiagnosticLevelInfo, `((a + b))`}}},
+		{`-(-x)`, []reported{{codes.DoubleNegation, linter.DiagnosticLevelInfo, `-(-x)`}}},
+		{`abs(sum(sum by (job) (up)))`, []reported{{codes.NestedAggregation, linter.DiagnosticLevelInfo, `sum(sum by (job) (up))`}}},
+		{`sum(sum(x) by (job))`, []reported{{codes.NestedAggregation, linter.DiagnosticLevelInfo, `sum(sum(x) by (job))`}}},
+		{`300 * rate(x[5m])`, []reported{{codes.RateMultiplication, linter.DiagnosticLevelInfo, `300 * rate(x[5m])`}}},
+		{`up > 0 and up < 10`, []reported{{codes.RangeFilter, linter.DiagnosticLevelInfo, `up > 0 and up < 10`}}},
+		// the range of `rate` is 300s, not 301s.
+		{`rate(x[5m]) * 301`, []reported{}},
+		// the inner aggregation drops the label that the outer one groups by.
+		{`sum by (job) (sum(x))`, []reported{}},
+		{`up > 0 and down < 10`, []reported{}},
+	}
+
+	for _, tt := range tests {
+		p := plugin.NewSimplifyPlugin(linter.PromQLinterColorModeDisable)
+		assert.Equal(t, tt.expected, pluginDiagnostics(t, tt.expr, nil, p), tt.expr)
+	}
+}
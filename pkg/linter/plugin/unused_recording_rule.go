@@ -0,0 +1,103 @@
+/*
+MIT License
+
+# Copyright (c) 2022 Drumato
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package plugin
+
+import (
+	"fmt"
+
+	"github.com/Drumato/promqlinter/pkg/codes"
+	"github.com/Drumato/promqlinter/pkg/linter"
+	"github.com/Drumato/promqlinter/pkg/rulegraph"
+	"github.com/prometheus/prometheus/promql/parser"
+)
+
+type unusedRecordingRule struct {
+	graph *rulegraph.Graph
+	// dashboardIdents is the set of the identifiers in the dashboard queries.
+	// it's nil if no dashboards are given.
+	dashboardIdents map[string]struct{}
+	color           linter.PromQLinterColorMode
+}
+
+// Execute implements linter.PromQLinterPlugin
+func (u *unusedRecordingRule) Execute(expr parser.Expr) (linter.Diagnostics, error) {
+	return u.ExecuteWithContext(expr, &linter.ExprContext{Kind: linter.ExprKindQuery})
+}
+
+// ExecuteWithContext implements linter.PromQLinterContextPlugin
+func (u *unusedRecordingRule) ExecuteWithContext(
+	expr parser.Expr,
+	ctx *linter.ExprContext,
+) (linter.Diagnostics, error) {
+	ds := linter.NewDiagnostics()
+
+	current, ok := u.graph.RuleOf(ctx)
+	if !ok || current.Record == "" {
+		return ds, nil
+	}
+
+	for _, r := range u.graph.Dependents(current.Record) {
+		if r != current {
+			return ds, nil
+		}
+	}
+
+	if u.dashboardIdents == nil {
+		msg := fmt.Sprintf("the recording rule `%s` is never read by any rule", current.Record)
+		ds.Add(linter.WarningDiagnostic(expr.PositionRange(), msg, u.color).WithCode(codes.UnusedRecordingRule))
+		return ds, nil
+	}
+
+	if _, ok := u.dashboardIdents[current.Record]; !ok {
+		msg := fmt.Sprintf("the recording rule `%s` is never read by any rule or dashboard", current.Record)
+		ds.Add(linter.WarningDiagnostic(expr.PositionRange(), msg, u.color).WithCode(codes.UnusedRecordingRule))
+	}
+
+	return ds, nil
+}
+
+// Name implements linter.PromQLinterPlugin
+func (*unusedRecordingRule) Name() string {
+	return "unused-recording-rules"
+}
+
+// NewUnusedRecordingRulePlugin creates an unused-recording-rules plugin.
+// dashboardIdents are the identifiers in the dashboard queries;
+// the dashboards are not considered if it's nil.
+func NewUnusedRecordingRulePlugin(
+	graph *rulegraph.Graph,
+	dashboardIdents []string,
+	color linter.PromQLinterColorMode,
+) linter.PromQLinterContextPlugin {
+	u := &unusedRecordingRule{graph: graph, color: color}
+	if dashboardIdents != nil {
+		u.dashboardIdents = map[string]struct{}{}
+		for _, ident := range dashboardIdents {
+			u.dashboardIdents[ident] = struct{}{}
+		}
+	}
+
+	return u
+}
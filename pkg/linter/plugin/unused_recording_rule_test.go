@@ -0,0 +1,104 @@
+/*
+MIT License
+
+# Copyright (c) 2022 Drumato
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package plugin_test
+
+import (
+	"testing"
+
+	"github.com/Drumato/promqlinter/pkg/codes"
+	"github.com/Drumato/promqlinter/pkg/linter"
+	"github.com/Drumato/promqlinter/pkg/linter/plugin"
+	"github.com/Drumato/promqlinter/pkg/manifest"
+	"github.com/Drumato/promqlinter/pkg/rulegraph"
+	"github.com/stretchr/testify/assert"
+)
+
+const unusedRecordingRulesManifest = `spec:
+  groups:
+  - name: example
+    rules:
+    - record: job:used:sum
+      expr: sum by (job) (up)
+    - alert: Down
+      expr: job:used:sum == 0
+    - record: job:dashboard:sum
+      expr: sum by (job) (up)
+    - record: job:self:sum
+      expr: job:self:sum offset 1h
+`
+
+func TestUnusedRecordingRules(t *testing.T) {
+	f, err := manifest.Parse("rules.yaml", []byte(unusedRecordingRulesManifest))
+	assert.NoError(t, err)
+	g := rulegraph.New(f.Rules)
+
+	tests := []struct {
+		rule            *manifest.Rule
+		dashboardIdents []string
+		expected        []string
+	}{
+		{f.Rules[0], nil, nil},
+		{f.Rules[1], nil, nil},
+		{f.Rules[2], nil, []string{"[WARN]", "the recording rule `job:dashboard:sum` is never read by any rule"}},
+		{f.Rules[2], []string{"job:dashboard:sum"}, nil},
+		{f.Rules[3], []string{"job:dashboard:sum"}, []string{"[WARN]", "the recording rule `job:self:sum` is never read by any rule or dashboard"}},
+	}
+
+	for _, tt := range tests {
+		p := plugin.NewUnusedRecordingRulePlugin(g, tt.dashboardIdents, linter.PromQLinterColorModeDisable)
+		out := pluginTestWithContext(t, tt.rule.Expr.StrVal, tt.rule.Context(), p)
+
+		if tt.expected == nil {
+			assert.Empty(t, out, tt.rule.Location())
+		}
+		for _, s := range tt.expected {
+			assert.Contains(t, out, s, tt.rule.Location())
+		}
+	}
+}
+
+func TestUnusedRecordingRulesDiagnostics(t *testing.T) {
+	f, err := manifest.Parse("rules.yaml", []byte(unusedRecordingRulesManifest))
+	assert.NoError(t, err)
+	g := rulegraph.New(f.Rules)
+
+	tests := []struct {
+		rule     *manifest.Rule
+		expected []reported
+	}{
+		// the whole expression of the rule is pointed.
+		{f.Rules[2], []reported{{codes.UnusedRecordingRule, linter.DiagnosticLevelWarning, `sum by (job) (up)`}}},
+		// reading itself doesn't make the rule used.
+		{f.Rules[3], []reported{{codes.UnusedRecordingRule, linter.DiagnosticLevelWarning, `job:self:sum offset 1h`}}},
+		{f.Rules[0], []reported{}},
+		// only the recording rules are checked.
+		{f.Rules[1], []reported{}},
+	}
+
+	for _, tt := range tests {
+		p := plugin.NewUnusedRecordingRulePlugin(g, nil, linter.PromQLinterColorModeDisable)
+		assert.Equal(t, tt.expected, pluginDiagnostics(t, tt.rule.Expr.StrVal, tt.rule.Context(), p), tt.rule.Location())
+	}
+}
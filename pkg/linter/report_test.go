@@ -0,0 +1,90 @@
+/*
+MIT License
+
+# Copyright (c) 2022 Drumato
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package linter_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/Drumato/promqlinter/pkg/linter"
+	"github.com/Drumato/promqlinter/pkg/linter/plugin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReportCodes(t *testing.T) {
+	tests := []struct {
+		rawExpr     string
+		options     []linter.PromQLinterOption
+		expected    []string
+		notExpected []string
+	}{
+		{
+			rawExpr: `up{job="a", job="b"}`,
+			expected: []string{
+				"label-matchers<[ERROR] PQL0201 (1:1)",
+				"see https://github.com/Drumato/promqlinter/blob/main/pkg/codes/docs/PQL0201.md",
+			},
+		},
+		{
+			rawExpr:  `up{job="a", job="b"}`,
+			options:  []linter.PromQLinterOption{linter.WithDocURLTemplate("https://example.com/{code}")},
+			expected: []string{"see https://example.com/PQL0201"},
+		},
+		{
+			rawExpr:     `up{job="a", job="b"}`,
+			options:     []linter.PromQLinterOption{linter.WithDocURLTemplate("")},
+			expected:    []string{"label-matchers<[ERROR] PQL0201 (1:1)"},
+			notExpected: []string{"see "},
+		},
+		{
+			rawExpr:  `sum(up`,
+			expected: []string{"promql/parser<[ERROR] PQL0001"},
+		},
+		{
+			rawExpr:  "up # promqlinter:ignore label-matchers",
+			expected: []string{"suppressions<[WARN] PQL0002"},
+		},
+	}
+
+	for _, tt := range tests {
+		out := &bytes.Buffer{}
+		options := append([]linter.PromQLinterOption{
+			linter.WithPlugin(plugin.NewLabelMatcherPlugin(linter.PromQLinterColorModeDisable)),
+			linter.WithOutStream(out),
+			linter.WithANSIColorMode(linter.PromQLinterColorModeDisable),
+		}, tt.options...)
+		l := linter.New(options...)
+
+		_, err := l.Execute(tt.rawExpr, linter.DiagnosticLevelInfo)
+		assert.NoError(t, err)
+
+		for _, e := range tt.expected {
+			assert.Contains(t, out.String(), e)
+		}
+		for _, e := range tt.notExpected {
+			assert.NotContains(t, out.String(), e)
+		}
+	}
+}
@@ -0,0 +1,214 @@
+/*
+MIT License
+
+# Copyright (c) 2022 Drumato
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package linter
+
+import (
+	"encoding/json"
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/Drumato/promqlinter/pkg/codes"
+)
+
+const (
+	// SeverityOff disables the diagnostics in SeverityOverride.
+	SeverityOff SeverityLevel = "off"
+)
+
+// SeverityLevel is the level name (info/warning/error/off) in SeverityOverride.
+type SeverityLevel string
+
+// UnmarshalJSON implements json.Unmarshaler
+// YAML 1.1 decodes the unquoted `off` as false, so false is regarded as "off".
+func (l *SeverityLevel) UnmarshalJSON(b []byte) error {
+	if string(b) == "false" {
+		*l = SeverityOff
+		return nil
+	}
+
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return err
+	}
+
+	*l = SeverityLevel(s)
+	return nil
+}
+
+// SeverityOverride changes the level of the diagnostics that a plugin reports.
+type SeverityOverride struct {
+	// Plugin is the name of the plugin whose diagnostics are overridden.
+	// the override applies to all the plugins if it's empty and Codes is specified.
+	Plugin string `json:"plugin,omitempty"`
+	// Codes limits the override to the diagnostics with any of the codes, e.g., `PQL0202`.
+	Codes []string `json:"codes,omitempty"`
+	// Level is the new level (info/warning/error/off).
+	// the diagnostics are not reported if it's "off".
+	Level SeverityLevel `json:"level"`
+	// Files limits the override to the manifests that match any of the glob patterns.
+	// the pattern is matched against the path and its base name.
+	// the override applies to all the expressions if it's empty.
+	Files []string `json:"files,omitempty"`
+	// Groups limits the override to the rule groups with any of the names.
+	// the override applies to all the expressions if it's empty.
+	Groups []string `json:"groups,omitempty"`
+}
+
+// Validate checks the level and the patterns of the override.
+func (o *SeverityOverride) Validate() error {
+	if o.Plugin == "" && len(o.Codes) == 0 {
+		return fmt.Errorf("the plugin or the codes of the severity override must be specified")
+	}
+
+	subject := o.Plugin
+	if subject == "" {
+		subject = strings.Join(o.Codes, ",")
+	}
+
+	if o.Level != SeverityOff {
+		if _, err := ParseDiagnosticLevel(string(o.Level)); err != nil {
+			return fmt.Errorf("the severity override of `%s`: %w", subject, err)
+		}
+	}
+
+	for _, code := range o.Codes {
+		// the codes of the custom plugins don't have the built-in prefix.
+		if _, ok := codes.Lookup(code); !ok && strings.HasPrefix(strings.ToUpper(code), "PQL") {
+			return fmt.Errorf("the severity override of `%s`: unknown code `%s`", subject, code)
+		}
+	}
+
+	for _, pattern := range o.Files {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("the severity override of `%s`: invalid file pattern %q", subject, pattern)
+		}
+	}
+
+	return nil
+}
+
+// appliesTo determines whether the override applies to the diagnostic of the plugin.
+func (o *SeverityOverride) appliesTo(pluginName string, code string, ctx *ExprContext) bool {
+	if o.Plugin != "" && o.Plugin != pluginName {
+		return false
+	}
+
+	if len(o.Codes) != 0 {
+		matched := false
+		for _, c := range o.Codes {
+			if strings.EqualFold(c, code) {
+				matched = true
+			}
+		}
+
+		if !matched {
+			return false
+		}
+	}
+
+	if len(o.Files) != 0 {
+		matched := false
+		for _, pattern := range o.Files {
+			if ok, _ := path.Match(pattern, ctx.File); ok {
+				matched = true
+			}
+			if ok, _ := path.Match(pattern, path.Base(ctx.File)); ok && ctx.File != "" {
+				matched = true
+			}
+		}
+
+		if !matched {
+			return false
+		}
+	}
+
+	if len(o.Groups) != 0 {
+		matched := false
+		for _, group := range o.Groups {
+			if group == ctx.GroupName {
+				matched = true
+			}
+		}
+
+		if !matched {
+			return false
+		}
+	}
+
+	return true
+}
+
+// overrideSeverity applies the overrides to the diagnostic.
+// the last matched override wins.
+// it returns false if the diagnostic is turned off.
+func overrideSeverity(
+	overrides []SeverityOverride,
+	pluginName string,
+	ctx *ExprContext,
+	d Diagnostic,
+) (Diagnostic, bool) {
+	var matched *SeverityOverride
+	for i := range overrides {
+		if overrides[i].appliesTo(pluginName, diagnosticCode(d), ctx) {
+			matched = &overrides[i]
+		}
+	}
+
+	if matched == nil {
+		return d, true
+	}
+	if matched.Level == SeverityOff {
+		return d, false
+	}
+
+	level, err := ParseDiagnosticLevel(string(matched.Level))
+	if err != nil {
+		// the invalid overrides are ignored.
+		return d, true
+	}
+
+	ld, ok := d.(LevelOverridableDiagnostic)
+	if !ok {
+		// the level of the diagnostic can't be changed.
+		return d, true
+	}
+
+	return ld.WithLevel(level), true
+}
+
+// ParseDiagnosticLevel parses the level name (info/warning/error).
+func ParseDiagnosticLevel(s string) (DiagnosticLevel, error) {
+	switch s {
+	case "info":
+		return DiagnosticLevelInfo, nil
+	case "warning":
+		return DiagnosticLevelWarning, nil
+	case "error":
+		return DiagnosticLevelError, nil
+	default:
+		return DiagnosticLevelInfo, fmt.Errorf("the level must be one of info/warning/error/off, but got %q", s)
+	}
+}
@@ -0,0 +1,161 @@
+/*
+MIT License
+
+# Copyright (c) 2022 Drumato
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package linter_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/Drumato/promqlinter/pkg/linter"
+	"github.com/Drumato/promqlinter/pkg/linter/plugin"
+	"github.com/stretchr/testify/assert"
+	"sigs.k8s.io/yaml"
+)
+
+func TestSeverityOverrides(t *testing.T) {
+	const rawExpr = `up{job="a", job="b"}`
+
+	tests := []struct {
+		overrides   []linter.SeverityOverride
+		ctx         *linter.ExprContext
+		expected    []string
+		notExpected []string
+	}{
+		{
+			overrides: nil,
+			ctx:       &linter.ExprContext{},
+			expected:  []string{"label-matchers<[ERROR]"},
+		},
+		{
+			overrides: []linter.SeverityOverride{{Plugin: "label-matchers", Level: "warning"}},
+			ctx:       &linter.ExprContext{},
+			expected:  []string{"label-matchers<[WARN]"},
+		},
+		{
+			overrides:   []linter.SeverityOverride{{Plugin: "label-matchers", Level: linter.SeverityOff}},
+			ctx:         &linter.ExprContext{},
+			notExpected: []string{"label-matchers<"},
+		},
+		{
+			overrides: []linter.SeverityOverride{
+				{Plugin: "label-matchers", Level: "info"},
+				{Plugin: "label-matchers", Level: "warning"},
+			},
+			ctx:      &linter.ExprContext{},
+			expected: []string{"label-matchers<[WARN]"},
+		},
+		{
+			overrides: []linter.SeverityOverride{{Plugin: "label-matchers", Level: "info", Files: []string{"legacy/*.yaml"}}},
+			ctx:       &linter.ExprContext{File: "legacy/rules.yaml"},
+			expected:  []string{"label-matchers<[INFO]"},
+		},
+		{
+			overrides: []linter.SeverityOverride{{Plugin: "label-matchers", Level: "info", Files: []string{"legacy-*.yaml"}}},
+			ctx:       &linter.ExprContext{File: "manifests/legacy-rules.yaml"},
+			expected:  []string{"label-matchers<[INFO]"},
+		},
+		{
+			overrides: []linter.SeverityOverride{{Plugin: "label-matchers", Level: "info", Files: []string{"legacy/*.yaml"}}},
+			ctx:       &linter.ExprContext{File: "new/rules.yaml"},
+			expected:  []string{"label-matchers<[ERROR]"},
+		},
+		{
+			overrides: []linter.SeverityOverride{{Plugin: "label-matchers", Level: "info", Groups: []string{"legacy"}}},
+			ctx:       &linter.ExprContext{GroupName: "legacy"},
+			expected:  []string{"label-matchers<[INFO]"},
+		},
+		{
+			overrides: []linter.SeverityOverride{{Plugin: "label-matchers", Level: "info", Groups: []string{"legacy"}}},
+			ctx:       &linter.ExprContext{GroupName: "new"},
+			expected:  []string{"label-matchers<[ERROR]"},
+		},
+		{
+			overrides: []linter.SeverityOverride{{Codes: []string{"PQL0201"}, Level: "warning"}},
+			ctx:       &linter.ExprContext{},
+			expected:  []string{"label-matchers<[WARN] PQL0201"},
+		},
+		{
+			overrides:   []linter.SeverityOverride{{Plugin: "label-matchers", Codes: []string{"pql0201"}, Level: linter.SeverityOff}},
+			ctx:         &linter.ExprContext{},
+			notExpected: []string{"label-matchers<"},
+		},
+		{
+			overrides: []linter.SeverityOverride{{Codes: []string{"PQL0202"}, Level: "info"}},
+			ctx:       &linter.ExprContext{},
+			expected:  []string{"label-matchers<[ERROR] PQL0201"},
+		},
+		{
+			overrides: []linter.SeverityOverride{{Plugin: "simplify", Codes: []string{"PQL0201"}, Level: "info"}},
+			ctx:       &linter.ExprContext{},
+			expected:  []string{"label-matchers<[ERROR] PQL0201"},
+		},
+	}
+
+	for _, tt := range tests {
+		out := &bytes.Buffer{}
+		l := linter.New(
+			linter.WithOutStream(out),
+			linter.WithPlugins(plugin.NewLabelMatcherPlugin(linter.PromQLinterColorModeDisable)),
+			linter.WithSeverityOverrides(tt.overrides...),
+		)
+
+		_, err := l.ExecuteWithContext(rawExpr, tt.ctx, linter.DiagnosticLevelInfo)
+		assert.NoError(t, err)
+
+		for _, s := range tt.expected {
+			assert.Contains(t, out.String(), s)
+		}
+		for _, s := range tt.notExpected {
+			assert.NotContains(t, out.String(), s)
+		}
+	}
+}
+
+func TestSeverityOverrideValidate(t *testing.T) {
+	tests := []struct {
+		yaml  string
+		valid bool
+	}{
+		{"plugin: a\nlevel: warning", true},
+		{"plugin: a\nlevel: off", true},
+		{"plugin: a\nlevel: fatal", false},
+		{"level: info", false},
+		{"codes: [PQL0201]\nlevel: info", true},
+		{"codes: [MY0001]\nlevel: info", true},
+		{"codes: [PQL9999]\nlevel: info", false},
+		{"plugin: a\nlevel: info\nfiles: ['[']", false},
+	}
+
+	for _, tt := range tests {
+		o := linter.SeverityOverride{}
+		assert.NoError(t, yaml.Unmarshal([]byte(tt.yaml), &o))
+
+		if tt.valid {
+			assert.NoError(t, o.Validate(), tt.yaml)
+		} else {
+			assert.Error(t, o.Validate(), tt.yaml)
+		}
+	}
+}
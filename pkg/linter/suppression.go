@@ -0,0 +1,208 @@
+/*
+MIT License
+
+# Copyright (c) 2022 Drumato
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package linter
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Drumato/promqlinter/pkg/codes"
+	"github.com/Drumato/promqlinter/pkg/promqlutil"
+	"github.com/prometheus/prometheus/promql/parser"
+)
+
+const (
+	// suppressionPrefix is the prefix of all the directives in comments.
+	suppressionPrefix = "promqlinter:"
+	// suppressionDirective silences the diagnostics of the given plugins.
+	// e.g., `# promqlinter:ignore denied-labels,query-cost the reason`
+	suppressionDirective = suppressionPrefix + "ignore"
+	// suppressionPluginName is the plugin name of the diagnostics about the suppressions.
+	suppressionPluginName = "suppressions"
+)
+
+// suppression silences the diagnostics of the plugins.
+type suppression struct {
+	// comment is the comment that the suppression is written in.
+	comment string
+	plugins []string
+	reason  string
+	// line is the line in the expression whose diagnostics are silenced.
+	// zero means the whole expression.
+	line int
+	// position is the position of the comment in the expression.
+	position parser.PositionRange
+	used     bool
+}
+
+// suppresses determines whether the suppression silences the diagnostic.
+func (s *suppression) suppresses(pluginName string, line int) bool {
+	if s.line != 0 && s.line != line {
+		return false
+	}
+
+	for _, p := range s.plugins {
+		if p == pluginName {
+			return true
+		}
+	}
+
+	return false
+}
+
+// suppressed determines whether any suppression silences the diagnostic of the plugin.
+// all the matched suppressions are marked as used.
+func suppressed(suppressions []*suppression, pluginName string, rawExpr *string, d Diagnostic) bool {
+	if len(suppressions) == 0 {
+		return false
+	}
+
+	// the diagnostics without the position are only silenced by the suppressions of the whole expression.
+	line := 0
+	if pd, ok := d.(PositionedDiagnostic); ok {
+		line = promqlutil.ConvertPosTo2d(rawExpr, pd.Position()).Line
+	}
+	found := false
+	for _, s := range suppressions {
+		if s.suppresses(pluginName, line) {
+			s.used = true
+			found = true
+		}
+	}
+
+	return found
+}
+
+// collectSuppressions collects the suppressions from the comments above the rule
+// and the comments in the expression.
+// pluginNames is the set of the known plugins.
+// the malformed suppressions are returned as diagnostics.
+func collectSuppressions(
+	rawExpr string,
+	ctx *ExprContext,
+	pluginNames map[string]struct{},
+	color PromQLinterColorMode,
+) ([]*suppression, []Diagnostic) {
+	suppressions := []*suppression{}
+	ds := []Diagnostic{}
+
+	add := func(comment string, line int, position parser.PositionRange) {
+		s, err := parseSuppression(comment, pluginNames)
+		if err != nil {
+			msg := fmt.Sprintf("the malformed suppression `%s`: %s", comment, err)
+			ds = append(ds, WarningDiagnostic(position, msg, color).WithCode(codes.MalformedSuppression))
+			return
+		}
+		if s == nil {
+			return
+		}
+
+		s.line = line
+		s.position = position
+		suppressions = append(suppressions, s)
+	}
+
+	// the suppressions above the rule are reported at the first line of the expression.
+	firstLine := parser.PositionRange{Start: 0, End: parser.Pos(len(rawExpr))}
+	if i := strings.Index(rawExpr, "\n"); i >= 0 {
+		firstLine.End = parser.Pos(i)
+	}
+	for _, comment := range ctx.Comments {
+		add(comment, 0, firstLine)
+	}
+
+	l := parser.Lex(rawExpr)
+	for {
+		var item parser.Item
+		l.NextItem(&item)
+		if item.Typ == parser.EOF || item.Typ == parser.ERROR {
+			break
+		}
+		if item.Typ != parser.COMMENT {
+			continue
+		}
+
+		position := parser.PositionRange{Start: item.Pos, End: item.Pos + parser.Pos(len(item.Val))}
+		line := promqlutil.ConvertPosTo2d(&rawExpr, position).Line
+
+		// a comment on its own line silences the next line.
+		lineStart := strings.LastIndex(rawExpr[:item.Pos], "\n") + 1
+		if strings.TrimSpace(rawExpr[lineStart:item.Pos]) == "" {
+			line++
+		}
+
+		add(item.Val, line, position)
+	}
+
+	return suppressions, ds
+}
+
+// parseSuppression parses a suppression directive in the given comment.
+// it returns nil without any error if the comment isn't a directive.
+func parseSuppression(comment string, pluginNames map[string]struct{}) (*suppression, error) {
+	text := strings.TrimSpace(strings.TrimLeft(strings.TrimSpace(comment), "#"))
+	if !strings.HasPrefix(text, suppressionPrefix) {
+		return nil, nil
+	}
+
+	fields := strings.Fields(text)
+	if fields[0] != suppressionDirective {
+		return nil, fmt.Errorf("unknown directive `%s`; use `%s`", fields[0], suppressionDirective)
+	}
+	if len(fields) < 2 {
+		return nil, fmt.Errorf("no plugins are specified")
+	}
+	if len(fields) < 3 {
+		return nil, fmt.Errorf("the reason is required")
+	}
+
+	s := &suppression{
+		comment: comment,
+		plugins: strings.Split(fields[1], ","),
+		reason:  strings.Join(fields[2:], " "),
+	}
+	for _, p := range s.plugins {
+		if _, ok := pluginNames[p]; !ok {
+			return nil, fmt.Errorf("unknown plugin `%s`", p)
+		}
+	}
+
+	return s, nil
+}
+
+// unusedSuppressionDiagnostics returns the diagnostics of the suppressions that silence nothing.
+func unusedSuppressionDiagnostics(suppressions []*suppression, color PromQLinterColorMode) []Diagnostic {
+	ds := []Diagnostic{}
+	for _, s := range suppressions {
+		if s.used {
+			continue
+		}
+
+		msg := fmt.Sprintf("the suppression `%s` silences nothing; remove it", strings.TrimSpace(s.comment))
+		ds = append(ds, WarningDiagnostic(s.position, msg, color).WithCode(codes.UnusedSuppression))
+	}
+
+	return ds
+}
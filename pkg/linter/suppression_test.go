@@ -0,0 +1,107 @@
+/*
+MIT License
+
+# Copyright (c) 2022 Drumato
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package linter_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/Drumato/promqlinter/pkg/linter"
+	"github.com/Drumato/promqlinter/pkg/linter/plugin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSuppressions(t *testing.T) {
+	tests := []struct {
+		expr        string
+		comments    []string
+		expected    []string
+		notExpected []string
+	}{
+		{
+			expr:     `up{job="a", job="b"}`,
+			expected: []string{"label-matchers<", "contradicts"},
+		},
+		{
+			expr:        `up{job="a", job="b"}`,
+			comments:    []string{"# promqlinter:ignore label-matchers the selector is generated"},
+			notExpected: []string{"label-matchers<", "suppressions<"},
+		},
+		{
+			expr:        "sum(\n  # promqlinter:ignore label-matchers the selector is generated\n  up{job=\"a\", job=\"b\"}\n)",
+			notExpected: []string{"label-matchers<", "suppressions<"},
+		},
+		{
+			expr:        "up{job=\"a\", job=\"b\"} # promqlinter:ignore label-matchers,simplify the selector is generated",
+			notExpected: []string{"label-matchers<", "suppressions<"},
+		},
+		{
+			expr:     "# promqlinter:ignore label-matchers the next line only\nsum(up)\n+ sum(up{job=\"a\", job=\"b\"})",
+			expected: []string{"label-matchers<", "suppressions<", "the suppression `# promqlinter:ignore label-matchers the next line only` silences nothing"},
+		},
+		{
+			expr:     `up`,
+			comments: []string{"# promqlinter:ignore label-matchers nothing to silence"},
+			expected: []string{"suppressions<[WARN]", "silences nothing; remove it"},
+		},
+		{
+			expr:     `up`,
+			comments: []string{"# promqlinter:ignore unknown-plugin reason"},
+			expected: []string{"suppressions<[WARN]", "the malformed suppression `# promqlinter:ignore unknown-plugin reason`: unknown plugin `unknown-plugin`"},
+		},
+		{
+			expr:     `up # promqlinter:ignore label-matchers`,
+			expected: []string{"suppressions<[WARN]", "the reason is required"},
+		},
+		{
+			expr:     `up # promqlinter:disable label-matchers reason`,
+			expected: []string{"suppressions<[WARN]", "unknown directive `promqlinter:disable`"},
+		},
+		{
+			expr:        `up # just a comment`,
+			notExpected: []string{"suppressions<"},
+		},
+	}
+
+	for _, tt := range tests {
+		out := &bytes.Buffer{}
+		l := linter.New(
+			linter.WithOutStream(out),
+			linter.WithANSIColorMode(linter.PromQLinterColorModeDisable),
+			linter.WithPlugins(plugin.DefaultsWithConfig(plugin.DefaultConfig(), linter.PromQLinterColorModeDisable)...),
+		)
+
+		ctx := &linter.ExprContext{Kind: linter.ExprKindQuery, Comments: tt.comments}
+		_, err := l.ExecuteWithContext(tt.expr, ctx, linter.DiagnosticLevelInfo)
+		assert.NoError(t, err)
+
+		for _, s := range tt.expected {
+			assert.Contains(t, out.String(), s, tt.expr)
+		}
+		for _, s := range tt.notExpected {
+			assert.NotContains(t, out.String(), s, tt.expr)
+		}
+	}
+}
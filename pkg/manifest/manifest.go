@@ -0,0 +1,231 @@
+/*
+MIT License
+
+# Copyright (c) 2022 Drumato
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+// Package manifest loads the rules of PrometheusRule manifests with their locations.
+package manifest
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/Drumato/promqlinter/pkg/linter"
+	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	yamlv3 "gopkg.in/yaml.v3"
+	"sigs.k8s.io/yaml"
+)
+
+// File is a PrometheusRule manifest.
+type File struct {
+	// Path is the path of the manifest.
+	Path string
+	// Manifest is the decoded manifest.
+	Manifest monitoringv1.PrometheusRule
+	// Rules is the list of the rules in the order of the appearance.
+	Rules []*Rule
+}
+
+// Rule is a rule of a PrometheusRule manifest.
+type Rule struct {
+	monitoringv1.Rule
+
+	// File is the path of the manifest that defines the rule.
+	File string
+	// GroupName is the name of the rule group.
+	GroupName string
+	// GroupIndex is the index of the rule group in the manifest.
+	GroupIndex int
+	// GroupInterval is the evaluation interval of the rule group.
+	// it's empty if the group doesn't specify it.
+	GroupInterval string
+	// Index is the index of the rule in the rule group.
+	Index int
+	// KeepFiringFor is the `keep_firing_for` duration of the alerting rule.
+	// it's read from the manifest directly since monitoringv1.Rule doesn't have the field.
+	KeepFiringFor string
+	// Comments are the comment lines above the rule and its fields.
+	Comments []string
+	// Line is the line number of the rule in the manifest.
+	// it's zero if unknown.
+	Line int
+	// ExprLine is the line number where the expression starts.
+	// it's zero if unknown.
+	ExprLine int
+}
+
+// Name returns the alert name or the recorded metric name.
+func (r *Rule) Name() string {
+	if r.Alert != "" {
+		return r.Alert
+	}
+
+	return r.Record
+}
+
+// Kind returns the kind of the rule expression.
+func (r *Rule) Kind() linter.ExprKind {
+	if r.Alert != "" {
+		return linter.ExprKindAlertingRule
+	}
+
+	return linter.ExprKindRecordingRule
+}
+
+// Location returns the location like `path/to/rules.yaml:12`.
+func (r *Rule) Location() string {
+	return fmt.Sprintf("%s:%d", r.File, r.Line)
+}
+
+// Context creates the linter context of the rule expression.
+func (r *Rule) Context() *linter.ExprContext {
+	return &linter.ExprContext{
+		Kind:          r.Kind(),
+		Name:          r.Name(),
+		For:           string(r.For),
+		KeepFiringFor: r.KeepFiringFor,
+		File:          r.File,
+		Line:          r.Line,
+		GroupName:     r.GroupName,
+		GroupIndex:    r.GroupIndex,
+		GroupInterval: r.GroupInterval,
+		RuleIndex:     r.Index,
+		Comments:      r.Comments,
+	}
+}
+
+// Load reads a PrometheusRule manifest.
+func Load(path string) (*File, error) {
+	out, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return Parse(path, out)
+}
+
+// Parse decodes a PrometheusRule manifest.
+// path is only used for the locations of the rules.
+func Parse(path string, out []byte) (*File, error) {
+	f := &File{Path: path}
+	if err := yaml.Unmarshal(out, &f.Manifest); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	// the nodes are used only for the locations.
+	// they are left unknown if the manifest can't be decoded as a node.
+	var root yamlv3.Node
+	groupNodes := []*yamlv3.Node{}
+	if err := yamlv3.Unmarshal(out, &root); err == nil {
+		groupNodes = sequenceItems(lookupNode(&root, "spec", "groups"))
+	}
+
+	for gi, rg := range f.Manifest.Spec.Groups {
+		var ruleNodes []*yamlv3.Node
+		if gi < len(groupNodes) {
+			ruleNodes = sequenceItems(lookupNode(groupNodes[gi], "rules"))
+		}
+
+		for ri, rule := range rg.Rules {
+			r := &Rule{
+				Rule:          rule,
+				File:          path,
+				GroupName:     rg.Name,
+				GroupIndex:    gi,
+				GroupInterval: string(rg.Interval),
+				Index:         ri,
+			}
+
+			if ri < len(ruleNodes) {
+				r.Line = ruleNodes[ri].Line
+				r.Comments = headComments(ruleNodes[ri])
+				if keepFiringForNode := lookupNode(ruleNodes[ri], "keep_firing_for"); keepFiringForNode != nil {
+					r.KeepFiringFor = keepFiringForNode.Value
+				}
+				if exprNode := lookupNode(ruleNodes[ri], "expr"); exprNode != nil {
+					r.ExprLine = exprNode.Line
+					if exprNode.Style&(yamlv3.LiteralStyle|yamlv3.FoldedStyle) != 0 {
+						// the contents of the block scalar start at the next line.
+						r.ExprLine++
+					}
+				}
+			}
+
+			f.Rules = append(f.Rules, r)
+		}
+	}
+
+	return f, nil
+}
+
+// lookupNode follows the given mapping keys from the node.
+// it returns nil if any key is not found.
+func lookupNode(node *yamlv3.Node, keys ...string) *yamlv3.Node {
+	if node != nil && node.Kind == yamlv3.DocumentNode && len(node.Content) != 0 {
+		node = node.Content[0]
+	}
+
+	for _, key := range keys {
+		if node == nil || node.Kind != yamlv3.MappingNode {
+			return nil
+		}
+
+		var next *yamlv3.Node
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			if node.Content[i].Value == key {
+				next = node.Content[i+1]
+				break
+			}
+		}
+		node = next
+	}
+
+	return node
+}
+
+// headComments returns the comment lines above the mapping node and its keys.
+func headComments(node *yamlv3.Node) []string {
+	comments := []string{}
+	nodes := []*yamlv3.Node{node}
+	for i := 0; i < len(node.Content); i += 2 {
+		nodes = append(nodes, node.Content[i])
+	}
+
+	for _, n := range nodes {
+		if n.HeadComment == "" {
+			continue
+		}
+		comments = append(comments, strings.Split(n.HeadComment, "\n")...)
+	}
+
+	return comments
+}
+
+// sequenceItems returns the items of the sequence node.
+func sequenceItems(node *yamlv3.Node) []*yamlv3.Node {
+	if node == nil || node.Kind != yamlv3.SequenceNode {
+		return nil
+	}
+
+	return node.Content
+}
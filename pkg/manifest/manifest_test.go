@@ -0,0 +1,92 @@
+/*
+MIT License
+
+# Copyright (c) 2022 Drumato
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package manifest_test
+
+import (
+	"testing"
+
+	"github.com/Drumato/promqlinter/pkg/linter"
+	"github.com/Drumato/promqlinter/pkg/manifest"
+	"github.com/stretchr/testify/assert"
+)
+
+const testManifest = `apiVersion: monitoring.coreos.com/v1
+kind: PrometheusRule
+metadata:
+  name: example
+spec:
+  groups:
+  - name: recording
+    rules:
+    - record: job:up:sum
+      expr: sum by (job) (up)
+  - name: alerting
+    interval: 30s
+    rules:
+    # promqlinter:ignore absent the reason
+    - alert: Down
+      expr: |
+        job:up:sum == 0
+      for: 5m
+      keep_firing_for: 10m
+`
+
+func TestParse(t *testing.T) {
+	f, err := manifest.Parse("rules.yaml", []byte(testManifest))
+	assert.NoError(t, err)
+	assert.Len(t, f.Rules, 2)
+
+	record := f.Rules[0]
+	assert.Equal(t, "job:up:sum", record.Name())
+	assert.Equal(t, linter.ExprKindRecordingRule, record.Kind())
+	assert.Equal(t, "recording", record.GroupName)
+	assert.Equal(t, 9, record.Line)
+	assert.Equal(t, 10, record.ExprLine)
+	assert.Equal(t, "rules.yaml:9", record.Location())
+
+	alert := f.Rules[1]
+	assert.Equal(t, "Down", alert.Name())
+	assert.Equal(t, linter.ExprKindAlertingRule, alert.Kind())
+	assert.Equal(t, 1, alert.GroupIndex)
+	assert.Equal(t, 0, alert.Index)
+	assert.Equal(t, 15, alert.Line)
+	assert.Equal(t, 17, alert.ExprLine)
+	assert.Equal(t, []string{"# promqlinter:ignore absent the reason"}, alert.Comments)
+	assert.Equal(t, "30s", alert.GroupInterval)
+	assert.Equal(t, "10m", alert.KeepFiringFor)
+
+	ctx := alert.Context()
+	assert.Equal(t, "5m", ctx.For)
+	assert.Equal(t, "10m", ctx.KeepFiringFor)
+	assert.Equal(t, "30s", ctx.GroupInterval)
+	assert.Equal(t, alert.Comments, ctx.Comments)
+	assert.Equal(t, "rules.yaml", ctx.File)
+	assert.Equal(t, "alerting", ctx.GroupName)
+}
+
+func TestParseInvalid(t *testing.T) {
+	_, err := manifest.Parse("rules.yaml", []byte("spec: ["))
+	assert.Error(t, err)
+}
@@ -0,0 +1,263 @@
+/*
+MIT License
+
+# Copyright (c) 2022 Drumato
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package promqlutil
+
+import (
+	"math"
+	"strconv"
+
+	"github.com/prometheus/prometheus/promql/parser"
+)
+
+// Constant is the value of a constant expression.
+type Constant struct {
+	// Value is the value of the scalar, or the sample value of the vector.
+	Value float64
+	// Vector determines whether the expression returns a vector like `vector(1)`.
+	Vector bool
+	// Empty determines whether the expression always returns an empty vector.
+	Empty bool
+}
+
+// String implements fmt.Stringer
+// it returns the PromQL expression that represents the constant.
+// note that an empty vector has no such expression.
+func (c *Constant) String() string {
+	var v string
+	switch {
+	case math.IsNaN(c.Value):
+		v = "NaN"
+	case math.IsInf(c.Value, 1):
+		v = "+Inf"
+	case math.IsInf(c.Value, -1):
+		v = "-Inf"
+	default:
+		v = strconv.FormatFloat(c.Value, 'f', -1, 64)
+	}
+
+	if c.Vector {
+		return "vector(" + v + ")"
+	}
+
+	return v
+}
+
+// constantFunctions are the functions that EvalConstant evaluates
+// if their only argument is constant.
+var constantFunctions = map[string]func(float64) float64{
+	"abs":   math.Abs,
+	"ceil":  math.Ceil,
+	"floor": math.Floor,
+	"exp":   math.Exp,
+	"sqrt":  math.Sqrt,
+	"ln":    math.Log,
+	"log2":  math.Log2,
+	"log10": math.Log10,
+}
+
+// EvalConstant evaluates the expression if it doesn't depend on any series or the evaluation time.
+// it returns false if the expression is not constant.
+func EvalConstant(expr parser.Expr) (*Constant, bool) {
+	switch e := expr.(type) {
+	case *parser.NumberLiteral:
+		return &Constant{Value: e.Val}, true
+	case *parser.ParenExpr:
+		return EvalConstant(e.Expr)
+	case *parser.StepInvariantExpr:
+		return EvalConstant(e.Expr)
+	case *parser.UnaryExpr:
+		c, ok := EvalConstant(e.Expr)
+		if !ok {
+			return nil, false
+		}
+		if e.Op == parser.SUB {
+			return &Constant{Value: -c.Value, Vector: c.Vector, Empty: c.Empty}, true
+		}
+
+		return c, true
+	case *parser.Call:
+		return evalConstantCall(e)
+	case *parser.BinaryExpr:
+		return evalConstantBinaryExpr(e)
+	default:
+		return nil, false
+	}
+}
+
+func evalConstantCall(e *parser.Call) (*Constant, bool) {
+	switch e.Func.Name {
+	case "pi":
+		return &Constant{Value: math.Pi}, true
+	case "vector":
+		c, ok := EvalConstant(e.Args[0])
+		if !ok {
+			return nil, false
+		}
+
+		return &Constant{Value: c.Value, Vector: true}, true
+	case "scalar":
+		c, ok := EvalConstant(e.Args[0])
+		if !ok {
+			return nil, false
+		}
+		if c.Empty {
+			return &Constant{Value: math.NaN()}, true
+		}
+
+		return &Constant{Value: c.Value}, true
+	}
+
+	f, ok := constantFunctions[e.Func.Name]
+	if !ok {
+		return nil, false
+	}
+
+	c, ok := EvalConstant(e.Args[0])
+	if !ok {
+		return nil, false
+	}
+
+	return &Constant{Value: f(c.Value), Vector: c.Vector, Empty: c.Empty}, true
+}
+
+func evalConstantBinaryExpr(e *parser.BinaryExpr) (*Constant, bool) {
+	lhs, lok := EvalConstant(e.LHS)
+	rhs, rok := EvalConstant(e.RHS)
+	if !lok || !rok {
+		// `time() - time()` is always zero even though time() is not constant.
+		if e.Op == parser.SUB && IsSameExpr(e.LHS, e.RHS) && isDeterministicScalar(e.LHS) {
+			return &Constant{Value: 0}, true
+		}
+
+		return nil, false
+	}
+
+	vector := lhs.Vector || rhs.Vector
+	switch e.Op {
+	case parser.LAND:
+		if lhs.Empty || rhs.Empty {
+			return &Constant{Vector: true, Empty: true}, true
+		}
+
+		return lhs, true
+	case parser.LOR:
+		if lhs.Empty {
+			return rhs, true
+		}
+
+		return lhs, true
+	case parser.LUNLESS:
+		if !rhs.Empty {
+			return &Constant{Vector: true, Empty: true}, true
+		}
+
+		return lhs, true
+	}
+
+	if lhs.Empty || rhs.Empty {
+		return &Constant{Vector: true, Empty: true}, true
+	}
+
+	if e.Op.IsComparisonOperator() {
+		matched := compareConstants(e.Op, lhs.Value, rhs.Value)
+		if e.ReturnBool {
+			v := 0.0
+			if matched {
+				v = 1
+			}
+
+			return &Constant{Value: v, Vector: vector}, true
+		}
+
+		if !matched {
+			return &Constant{Vector: true, Empty: true}, true
+		}
+		// the filter keeps the sample of the vector side.
+		if lhs.Vector {
+			return lhs, true
+		}
+
+		return rhs, true
+	}
+
+	v, ok := calcConstants(e.Op, lhs.Value, rhs.Value)
+	if !ok {
+		return nil, false
+	}
+
+	return &Constant{Value: v, Vector: vector}, true
+}
+
+func compareConstants(op parser.ItemType, lhs, rhs float64) bool {
+	switch op {
+	case parser.EQLC:
+		return lhs == rhs
+	case parser.NEQ:
+		return lhs != rhs
+	case parser.GTR:
+		return lhs > rhs
+	case parser.LSS:
+		return lhs < rhs
+	case parser.GTE:
+		return lhs >= rhs
+	case parser.LTE:
+		return lhs <= rhs
+	default:
+		// unreachable
+		return false
+	}
+}
+
+func calcConstants(op parser.ItemType, lhs, rhs float64) (float64, bool) {
+	switch op {
+	case parser.ADD:
+		return lhs + rhs, true
+	case parser.SUB:
+		return lhs - rhs, true
+	case parser.MUL:
+		return lhs * rhs, true
+	case parser.DIV:
+		return lhs / rhs, true
+	case parser.MOD:
+		return math.Mod(lhs, rhs), true
+	case parser.POW:
+		return math.Pow(lhs, rhs), true
+	case parser.ATAN2:
+		return math.Atan2(lhs, rhs), true
+	default:
+		return 0, false
+	}
+}
+
+// IsSameExpr determines whether the two expressions are the same except the formatting.
+func IsSameExpr(lhs, rhs parser.Expr) bool {
+	return lhs.String() == rhs.String()
+}
+
+// isDeterministicScalar determines whether the expression is a scalar
+// that doesn't depend on any series like `time()`.
+func isDeterministicScalar(expr parser.Expr) bool {
+	return expr.Type() == parser.ValueTypeScalar && len(parser.ExtractSelectors(expr)) == 0
+}
@@ -0,0 +1,72 @@
+/*
+MIT License
+
+# Copyright (c) 2022 Drumato
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package promqlutil_test
+
+import (
+	"testing"
+
+	"github.com/Drumato/promqlinter/pkg/promqlutil"
+	"github.com/prometheus/prometheus/promql/parser"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEvalConstant(t *testing.T) {
+	tests := []struct {
+		expr     string
+		expected string
+		empty    bool
+	}{
+		{`2 * 60`, "120", false},
+		{`(1 + 2) ^ 2 / 4`, "2.25", false},
+		{`-(5 % 3)`, "-2", false},
+		{`time() - time()`, "0", false},
+		{`vector(1) > 0`, "vector(1)", false},
+		{`1 < bool vector(2)`, "vector(1)", false},
+		{`vector(1) < 0`, "", true},
+		{`vector(1) or vector(2)`, "vector(1)", false},
+		{`scalar(abs(vector(-3))) + 1`, "4", false},
+		{`1 / 0`, "+Inf", false},
+	}
+
+	for _, tt := range tests {
+		expr, err := parser.ParseExpr(tt.expr)
+		assert.NoError(t, err)
+
+		c, ok := promqlutil.EvalConstant(expr)
+		assert.True(t, ok, tt.expr)
+		assert.Equal(t, tt.empty, c.Empty, tt.expr)
+		if !tt.empty {
+			assert.Equal(t, tt.expected, c.String(), tt.expr)
+		}
+	}
+
+	for _, s := range []string{`up`, `time()`, `time() - 1`, `scalar(up) - scalar(up)`, `vector(1) + up`} {
+		expr, err := parser.ParseExpr(s)
+		assert.NoError(t, err)
+
+		_, ok := promqlutil.EvalConstant(expr)
+		assert.False(t, ok, s)
+	}
+}
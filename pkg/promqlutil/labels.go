@@ -0,0 +1,294 @@
+/*
+MIT License
+
+# Copyright (c) 2022 Drumato
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package promqlutil
+
+import (
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/promql/parser"
+)
+
+// LabelSet is the knowledge about the labels of the series that an expression returns.
+type LabelSet struct {
+	// Exhaustive determines whether the series have no labels other than Present.
+	Exhaustive bool
+	// Present is the set of the labels that the series are known to have.
+	// if Exhaustive, the values of them may be empty (e.g., the grouping labels).
+	Present map[string]struct{}
+	// Dropped is the set of the labels that the series never have.
+	Dropped map[string]struct{}
+}
+
+// newLabelSet creates an empty LabelSet.
+func newLabelSet(exhaustive bool, present ...string) *LabelSet {
+	s := &LabelSet{
+		Exhaustive: exhaustive,
+		Present:    map[string]struct{}{},
+		Dropped:    map[string]struct{}{},
+	}
+	for _, name := range present {
+		s.add(name)
+	}
+
+	return s
+}
+
+// MayHave determines whether the series may have the label.
+func (s *LabelSet) MayHave(name string) bool {
+	if _, ok := s.Dropped[name]; ok {
+		return false
+	}
+
+	if s.Exhaustive {
+		_, ok := s.Present[name]
+		return ok
+	}
+
+	return true
+}
+
+// Has determines whether the series are known to have the label.
+func (s *LabelSet) Has(name string) bool {
+	_, ok := s.Present[name]
+	return ok
+}
+
+func (s *LabelSet) add(name string) {
+	s.Present[name] = struct{}{}
+	delete(s.Dropped, name)
+}
+
+func (s *LabelSet) drop(name string) {
+	delete(s.Present, name)
+	s.Dropped[name] = struct{}{}
+}
+
+// keep drops all labels except the given ones.
+func (s *LabelSet) keep(names ...string) *LabelSet {
+	kept := newLabelSet(true)
+	for _, name := range names {
+		if s.MayHave(name) {
+			kept.add(name)
+		}
+	}
+
+	return kept
+}
+
+// InferLabels infers the labels of the series that the expression returns.
+func InferLabels(expr parser.Expr) *LabelSet {
+	switch e := expr.(type) {
+	case *parser.VectorSelector:
+		s := newLabelSet(false)
+		for _, lm := range e.LabelMatchers {
+			if !lm.Matches("") {
+				s.add(lm.Name)
+			}
+		}
+
+		return s
+	case *parser.MatrixSelector:
+		return InferLabels(e.VectorSelector)
+	case *parser.SubqueryExpr:
+		return InferLabels(e.Expr)
+	case *parser.ParenExpr:
+		return InferLabels(e.Expr)
+	case *parser.StepInvariantExpr:
+		return InferLabels(e.Expr)
+	case *parser.UnaryExpr:
+		s := InferLabels(e.Expr)
+		s.drop(labels.MetricName)
+		return s
+	case *parser.AggregateExpr:
+		return inferAggregateLabels(e)
+	case *parser.Call:
+		return inferCallLabels(e)
+	case *parser.BinaryExpr:
+		return inferBinaryExprLabels(e)
+	default:
+		// number/string literals have no labels.
+		return newLabelSet(true)
+	}
+}
+
+func inferAggregateLabels(e *parser.AggregateExpr) *LabelSet {
+	var s *LabelSet
+	switch {
+	case e.Op == parser.TOPK || e.Op == parser.BOTTOMK:
+		return InferLabels(e.Expr)
+	case e.Without:
+		s = InferLabels(e.Expr)
+		s.drop(labels.MetricName)
+		for _, name := range e.Grouping {
+			s.drop(name)
+		}
+	default:
+		s = InferLabels(e.Expr).keep(e.Grouping...)
+	}
+
+	if e.Op == parser.COUNT_VALUES {
+		if param, ok := e.Param.(*parser.StringLiteral); ok {
+			s.add(param.Val)
+		}
+	}
+
+	return s
+}
+
+func inferCallLabels(e *parser.Call) *LabelSet {
+	switch e.Func.Name {
+	case "absent", "absent_over_time":
+		// the result carries the labels of the equality matchers only.
+		s := newLabelSet(true)
+		if vs, ok := unwrapSelector(e.Args[0]); ok {
+			for _, lm := range vs.LabelMatchers {
+				if lm.Type == labels.MatchEqual && lm.Name != labels.MetricName {
+					s.add(lm.Name)
+				}
+			}
+		}
+
+		return s
+	case "label_replace", "label_join":
+		s := InferLabels(e.Args[0])
+		if repl, ok := e.Args[2].(*parser.StringLiteral); ok && e.Func.Name == "label_replace" && repl.Val == "" {
+			// the empty replacement removes the destination label.
+			return s
+		}
+		if dst, ok := e.Args[1].(*parser.StringLiteral); ok {
+			s.add(dst.Val)
+		}
+
+		return s
+	case "sort", "sort_desc", "last_over_time":
+		return InferLabels(e.Args[0])
+	}
+
+	for _, arg := range e.Args {
+		if arg.Type() == parser.ValueTypeVector || arg.Type() == parser.ValueTypeMatrix {
+			s := InferLabels(arg)
+			s.drop(labels.MetricName)
+			return s
+		}
+	}
+
+	// e.g., vector() and time() return the series without labels.
+	return newLabelSet(true)
+}
+
+func inferBinaryExprLabels(e *parser.BinaryExpr) *LabelSet {
+	lhsVector := e.LHS.Type() == parser.ValueTypeVector
+	rhsVector := e.RHS.Type() == parser.ValueTypeVector
+
+	var s *LabelSet
+	switch {
+	case !lhsVector && !rhsVector:
+		return newLabelSet(true)
+	case !rhsVector:
+		s = InferLabels(e.LHS)
+	case !lhsVector:
+		s = InferLabels(e.RHS)
+	case e.Op == parser.LAND || e.Op == parser.LUNLESS:
+		return InferLabels(e.LHS)
+	case e.Op == parser.LOR:
+		return unionLabelSets(InferLabels(e.LHS), InferLabels(e.RHS))
+	default:
+		s = inferVectorMatchingLabels(e)
+	}
+
+	if !e.Op.IsComparisonOperator() || e.ReturnBool {
+		s.drop(labels.MetricName)
+	}
+
+	return s
+}
+
+func inferVectorMatchingLabels(e *parser.BinaryExpr) *LabelSet {
+	matching := e.VectorMatching
+	if matching == nil {
+		matching = &parser.VectorMatching{Card: parser.CardOneToOne}
+	}
+
+	many, one := e.LHS, e.RHS
+	if matching.Card == parser.CardOneToMany {
+		many, one = e.RHS, e.LHS
+	}
+
+	s := InferLabels(many)
+	if matching.Card == parser.CardOneToOne {
+		if matching.On {
+			s = s.keep(matching.MatchingLabels...)
+		} else {
+			for _, name := range matching.MatchingLabels {
+				s.drop(name)
+			}
+		}
+	}
+
+	oneSide := InferLabels(one)
+	for _, name := range matching.Include {
+		if oneSide.MayHave(name) {
+			s.add(name)
+		}
+	}
+
+	return s
+}
+
+// unionLabelSets merges the knowledge of the two label sets like the `or` operator.
+func unionLabelSets(lhs, rhs *LabelSet) *LabelSet {
+	s := newLabelSet(lhs.Exhaustive && rhs.Exhaustive)
+	for name := range lhs.Present {
+		if s.Exhaustive || rhs.Has(name) {
+			s.add(name)
+		}
+	}
+	if s.Exhaustive {
+		for name := range rhs.Present {
+			s.add(name)
+		}
+	}
+
+	for name := range lhs.Dropped {
+		if _, ok := rhs.Dropped[name]; ok {
+			s.drop(name)
+		}
+	}
+
+	return s
+}
+
+// unwrapSelector returns the series selector in the expression like `x` or `x[5m]`.
+func unwrapSelector(expr parser.Expr) (*parser.VectorSelector, bool) {
+	switch e := expr.(type) {
+	case *parser.VectorSelector:
+		return e, true
+	case *parser.MatrixSelector:
+		return unwrapSelector(e.VectorSelector)
+	case *parser.ParenExpr:
+		return unwrapSelector(e.Expr)
+	default:
+		return nil, false
+	}
+}
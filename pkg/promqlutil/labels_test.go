@@ -0,0 +1,70 @@
+/*
+MIT License
+
+# Copyright (c) 2022 Drumato
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package promqlutil_test
+
+import (
+	"testing"
+
+	"github.com/Drumato/promqlinter/pkg/promqlutil"
+	"github.com/prometheus/prometheus/promql/parser"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInferLabels(t *testing.T) {
+	tests := []struct {
+		expr    string
+		has     []string
+		mayHave []string
+		never   []string
+	}{
+		{`up{job="node"}`, []string{"__name__", "job"}, []string{"instance"}, nil},
+		{`sum by (job) (up)`, []string{"job"}, nil, []string{"instance", "__name__"}},
+		{`sum without (instance) (rate(up[5m]))`, nil, []string{"job"}, []string{"instance", "__name__"}},
+		{`count_values("version", build_info)`, []string{"version"}, nil, []string{"job"}},
+		{`a * on (job) group_left (team) b`, nil, []string{"instance", "team"}, []string{"__name__"}},
+		{`a / on (job) b`, nil, []string{"job"}, []string{"instance"}},
+		{`label_replace(sum(up), "host", "x", "", "")`, []string{"host"}, nil, []string{"job"}},
+		{`label_replace(sum(up), "host", "", "", "")`, nil, nil, []string{"host"}},
+		{`label_replace(sum by (host) (up), "host", "", "", "")`, nil, []string{"host"}, nil},
+		{`absent(up{job="node", instance=~"a.*"})`, []string{"job"}, nil, []string{"instance"}},
+		{`vector(1)`, nil, nil, []string{"job"}},
+	}
+
+	for _, tt := range tests {
+		expr, err := parser.ParseExpr(tt.expr)
+		assert.NoError(t, err)
+
+		s := promqlutil.InferLabels(expr)
+		for _, name := range tt.has {
+			assert.True(t, s.Has(name), "%s has %s", tt.expr, name)
+		}
+		for _, name := range tt.mayHave {
+			assert.True(t, s.MayHave(name), "%s may have %s", tt.expr, name)
+		}
+		for _, name := range tt.never {
+			assert.False(t, s.MayHave(name), "%s never has %s", tt.expr, name)
+		}
+	}
+}
@@ -26,6 +26,7 @@ package promqlutil
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/prometheus/prometheus/promql/parser"
 )
@@ -66,3 +67,75 @@ func ConvertPosTo2d(
 		Length: int(source.End) - int(source.Start),
 	}
 }
+
+// ParseExpr parses the expression like parser.ParseExpr and repairs the positions of the aggregations.
+// the parser extends an aggregation without the trailing modifier to the following closing bracket,
+// e.g., `sum(x)` in `abs(sum(x))` is reported as `sum(x))`.
+func ParseExpr(input string) (parser.Expr, error) {
+	expr, err := parser.ParseExpr(input)
+	if err != nil {
+		return expr, err
+	}
+
+	repairAggregatePositions(input, expr)
+	return expr, nil
+}
+
+// repairAggregatePositions repairs the positions of the aggregations from the innermost one
+// since the end of an aggregation is found after its (repaired) operand.
+func repairAggregatePositions(input string, node parser.Node) {
+	for _, child := range parser.Children(node) {
+		repairAggregatePositions(input, child)
+	}
+
+	agg, ok := node.(*parser.AggregateExpr)
+	if !ok {
+		return
+	}
+
+	closing := skipSpacesAndComments(input, int(agg.Expr.PositionRange().End))
+	if closing >= len(input) || input[closing] != ')' {
+		return
+	}
+
+	// the position is correct if the modifier follows the arguments like `sum(x) by (job)`.
+	next := strings.ToLower(input[skipSpacesAndComments(input, closing+1):])
+	if hasKeywordPrefix(next, "by") || hasKeywordPrefix(next, "without") {
+		return
+	}
+
+	agg.PosRange.End = parser.Pos(closing + 1)
+}
+
+// skipSpacesAndComments returns the index of the first character from i that is not a space or in a comment.
+func skipSpacesAndComments(input string, i int) int {
+	for i < len(input) {
+		switch input[i] {
+		case ' ', '\t', '\n', '\r':
+			i++
+		case '#':
+			for i < len(input) && input[i] != '\n' {
+				i++
+			}
+		default:
+			return i
+		}
+	}
+
+	return i
+}
+
+// hasKeywordPrefix determines whether s starts with the keyword as a whole word.
+func hasKeywordPrefix(s, keyword string) bool {
+	if !strings.HasPrefix(s, keyword) {
+		return false
+	}
+
+	rest := s[len(keyword):]
+	if rest == "" {
+		return true
+	}
+
+	c := rest[0]
+	return !(c == '_' || ('a' <= c && c <= 'z') || ('0' <= c && c <= '9'))
+}
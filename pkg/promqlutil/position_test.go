@@ -53,3 +53,36 @@ func TestConvertPosTo2d_Multi(t *testing.T) {
 	assert.Equal(t, 2, pos2d.Line)
 	assert.Equal(t, 5, pos2d.Column)
 }
+
+func TestParseExpr(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected []string
+	}{
+		{`abs(sum(x))`, []string{`sum(x)`}},
+		{`(sum(x))`, []string{`sum(x)`}},
+		{`sum(count(topk(5, x)) )`, []string{`sum(count(topk(5, x)) )`, `count(topk(5, x))`, `topk(5, x)`}},
+		{"sum(\n  x # )\n)", []string{"sum(\n  x # )\n)"}},
+		// the positions of the aggregations with the trailing modifiers are correct.
+		{`sum(sum(x) by (job))`, []string{`sum(sum(x) by (job))`, `sum(x) by (job)`}},
+		{`max(sum(x) WITHOUT (job))`, []string{`max(sum(x) WITHOUT (job))`, `sum(x) WITHOUT (job)`}},
+		{`max(sum by (job) (x))`, []string{`max(sum by (job) (x))`, `sum by (job) (x)`}},
+	}
+
+	for _, tt := range tests {
+		expr, err := promqlutil.ParseExpr(tt.input)
+		assert.NoError(t, err)
+
+		actual := []string{}
+		parser.Inspect(expr, func(n parser.Node, _ []parser.Node) error {
+			if agg, ok := n.(*parser.AggregateExpr); ok {
+				actual = append(actual, tt.input[agg.PosRange.Start:agg.PosRange.End])
+			}
+			return nil
+		})
+		assert.Equal(t, tt.expected, actual, tt.input)
+	}
+
+	_, err := promqlutil.ParseExpr(`sum(x`)
+	assert.Error(t, err)
+}
@@ -0,0 +1,92 @@
+/*
+MIT License
+
+# Copyright (c) 2022 Drumato
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package rulegraph
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+)
+
+// identifierRegexp matches the metric names in PromQL expressions.
+var identifierRegexp = regexp.MustCompile(`[a-zA-Z_:][a-zA-Z0-9_:]*`)
+
+// dashboardQueryKeys are the keys of the JSON fields that Grafana stores the queries in.
+// `expr` is used by the panel targets and `query` is used by the template variables.
+var dashboardQueryKeys = map[string]struct{}{
+	"expr":  {},
+	"query": {},
+}
+
+// LoadDashboard reads a Grafana dashboard JSON and returns the identifiers in its queries.
+func LoadDashboard(path string) ([]string, error) {
+	out, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	idents, err := ParseDashboard(out)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	return idents, nil
+}
+
+// ParseDashboard returns the identifiers in the queries of a Grafana dashboard JSON.
+// the queries are scanned instead of parsed since they may contain
+// the template variables like `$__rate_interval`.
+func ParseDashboard(out []byte) ([]string, error) {
+	var dashboard interface{}
+	if err := json.Unmarshal(out, &dashboard); err != nil {
+		return nil, err
+	}
+
+	idents := []string{}
+	var walk func(v interface{})
+	walk = func(v interface{}) {
+		switch v := v.(type) {
+		case map[string]interface{}:
+			for key, child := range v {
+				if s, ok := child.(string); ok {
+					if _, isQuery := dashboardQueryKeys[key]; isQuery {
+						idents = append(idents, identifierRegexp.FindAllString(s, -1)...)
+					}
+					continue
+				}
+
+				walk(child)
+			}
+		case []interface{}:
+			for _, child := range v {
+				walk(child)
+			}
+		}
+	}
+	walk(dashboard)
+
+	return idents, nil
+}
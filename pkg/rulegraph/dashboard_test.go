@@ -0,0 +1,62 @@
+/*
+MIT License
+
+# Copyright (c) 2022 Drumato
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package rulegraph_test
+
+import (
+	"testing"
+
+	"github.com/Drumato/promqlinter/pkg/rulegraph"
+	"github.com/stretchr/testify/assert"
+)
+
+const testDashboard = `{
+  "panels": [
+    {
+      "title": "job:ignored:title",
+      "targets": [{"expr": "sum(rate(http_requests_total[$__rate_interval])) / job:up:sum"}]
+    },
+    {
+      "type": "row",
+      "panels": [{"targets": [{"expr": "job:nested:sum{job=\"$job\"}"}]}]
+    }
+  ],
+  "templating": {
+    "list": [{"name": "job", "query": {"query": "label_values(job:var:sum, job)"}}]
+  }
+}`
+
+func TestParseDashboard(t *testing.T) {
+	idents, err := rulegraph.ParseDashboard([]byte(testDashboard))
+	assert.NoError(t, err)
+
+	assert.Contains(t, idents, "http_requests_total")
+	assert.Contains(t, idents, "job:up:sum")
+	assert.Contains(t, idents, "job:nested:sum")
+	assert.Contains(t, idents, "job:var:sum")
+	assert.NotContains(t, idents, "job:ignored:title")
+
+	_, err = rulegraph.ParseDashboard([]byte(`{`))
+	assert.Error(t, err)
+}
@@ -0,0 +1,161 @@
+/*
+MIT License
+
+# Copyright (c) 2022 Drumato
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package rulegraph
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+)
+
+// NodeKind is the kind of the node in the exported graph.
+type NodeKind string
+
+const (
+	// NodeKindMetric is a metric that no rule records, like the scraped ones.
+	NodeKindMetric NodeKind = "metric"
+	// NodeKindRecordingRule is a metric that recording rules record.
+	NodeKindRecordingRule NodeKind = "recording"
+	// NodeKindAlertingRule is an alert.
+	NodeKindAlertingRule NodeKind = "alerting"
+)
+
+// Node is a node of the exported graph.
+type Node struct {
+	// ID identifies the node in the graph.
+	ID string `json:"id"`
+	// Kind is the kind of the node.
+	Kind NodeKind `json:"kind"`
+	// Name is the metric name or the alert name.
+	Name string `json:"name"`
+	// Locations are the locations of the rules that define the node.
+	Locations []string `json:"locations,omitempty"`
+}
+
+// Edge is an edge of the exported graph.
+// the data flows from the From node to the To node.
+type Edge struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+// Export is the exported form of the graph.
+type Export struct {
+	Nodes []Node `json:"nodes"`
+	Edges []Edge `json:"edges"`
+}
+
+// Export converts the graph into the list of the nodes and edges.
+// the nodes and the edges are sorted so the output is stable.
+func (g *Graph) Export() *Export {
+	nodes := map[string]*Node{}
+	addNode := func(kind NodeKind, name string) *Node {
+		id := fmt.Sprintf("%s:%s", kind, name)
+		if n, ok := nodes[id]; ok {
+			return n
+		}
+
+		n := &Node{ID: id, Kind: kind, Name: name}
+		nodes[id] = n
+		return n
+	}
+
+	edges := map[Edge]struct{}{}
+	for _, r := range g.Rules {
+		kind := NodeKindRecordingRule
+		if r.Alert != "" {
+			kind = NodeKindAlertingRule
+		}
+		to := addNode(kind, r.Name())
+		to.Locations = append(to.Locations, r.Location())
+
+		for _, dep := range g.deps[r] {
+			from := NodeKindMetric
+			if _, ok := g.Records[dep]; ok {
+				from = NodeKindRecordingRule
+			}
+
+			edges[Edge{From: addNode(from, dep).ID, To: to.ID}] = struct{}{}
+		}
+	}
+
+	e := &Export{Nodes: []Node{}, Edges: []Edge{}}
+	for _, n := range nodes {
+		e.Nodes = append(e.Nodes, *n)
+	}
+	sort.Slice(e.Nodes, func(i, j int) bool { return e.Nodes[i].ID < e.Nodes[j].ID })
+
+	for edge := range edges {
+		e.Edges = append(e.Edges, edge)
+	}
+	sort.Slice(e.Edges, func(i, j int) bool {
+		if e.Edges[i].From != e.Edges[j].From {
+			return e.Edges[i].From < e.Edges[j].From
+		}
+		return e.Edges[i].To < e.Edges[j].To
+	})
+
+	return e
+}
+
+// WriteJSON outputs the graph in JSON.
+func (e *Export) WriteJSON(out io.Writer) error {
+	enc := json.NewEncoder(out)
+	enc.SetIndent("", "  ")
+	return enc.Encode(e)
+}
+
+// WriteDOT outputs the graph in the DOT language of Graphviz.
+func (e *Export) WriteDOT(out io.Writer) error {
+	shapes := map[NodeKind]string{
+		NodeKindMetric:        "ellipse",
+		NodeKindRecordingRule: "box",
+		NodeKindAlertingRule:  "octagon",
+	}
+
+	if _, err := fmt.Fprintln(out, "digraph rules {\n  rankdir=LR;"); err != nil {
+		return err
+	}
+
+	for _, n := range e.Nodes {
+		if _, err := fmt.Fprintf(
+			out, "  %s [label=%s, shape=%s];\n",
+			strconv.Quote(n.ID), strconv.Quote(n.Name), shapes[n.Kind],
+		); err != nil {
+			return err
+		}
+	}
+
+	for _, edge := range e.Edges {
+		if _, err := fmt.Fprintf(out, "  %s -> %s;\n", strconv.Quote(edge.From), strconv.Quote(edge.To)); err != nil {
+			return err
+		}
+	}
+
+	_, err := fmt.Fprintln(out, "}")
+	return err
+}
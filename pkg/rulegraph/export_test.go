@@ -0,0 +1,72 @@
+/*
+MIT License
+
+# Copyright (c) 2022 Drumato
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package rulegraph_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/Drumato/promqlinter/pkg/manifest"
+	"github.com/Drumato/promqlinter/pkg/rulegraph"
+	"github.com/stretchr/testify/assert"
+)
+
+const exportManifest = `spec:
+  groups:
+  - name: example
+    rules:
+    - record: job:up:sum
+      expr: sum by (job) (up)
+    - alert: Down
+      expr: job:up:sum == 0
+`
+
+func TestExport(t *testing.T) {
+	f, err := manifest.Parse("rules.yaml", []byte(exportManifest))
+	assert.NoError(t, err)
+	e := rulegraph.New(f.Rules).Export()
+
+	assert.Equal(t, []rulegraph.Node{
+		{ID: "alerting:Down", Kind: rulegraph.NodeKindAlertingRule, Name: "Down", Locations: []string{"rules.yaml:7"}},
+		{ID: "metric:up", Kind: rulegraph.NodeKindMetric, Name: "up"},
+		{ID: "recording:job:up:sum", Kind: rulegraph.NodeKindRecordingRule, Name: "job:up:sum", Locations: []string{"rules.yaml:5"}},
+	}, e.Nodes)
+	assert.Equal(t, []rulegraph.Edge{
+		{From: "metric:up", To: "recording:job:up:sum"},
+		{From: "recording:job:up:sum", To: "alerting:Down"},
+	}, e.Edges)
+
+	dot := &bytes.Buffer{}
+	assert.NoError(t, e.WriteDOT(dot))
+	assert.Contains(t, dot.String(), `"recording:job:up:sum" [label="job:up:sum", shape=box];`)
+	assert.Contains(t, dot.String(), `"recording:job:up:sum" -> "alerting:Down";`)
+
+	out := &bytes.Buffer{}
+	assert.NoError(t, e.WriteJSON(out))
+	decoded := rulegraph.Export{}
+	assert.NoError(t, json.Unmarshal(out.Bytes(), &decoded))
+	assert.Equal(t, *e, decoded)
+}
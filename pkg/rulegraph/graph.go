@@ -0,0 +1,188 @@
+/*
+MIT License
+
+# Copyright (c) 2022 Drumato
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+// Package rulegraph builds the dependency graph between the rules and the metrics they read.
+package rulegraph
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/Drumato/promqlinter/pkg/linter"
+	"github.com/Drumato/promqlinter/pkg/manifest"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/promql/parser"
+)
+
+// Graph is the dependency graph of the rules.
+type Graph struct {
+	// Rules is the list of all the rules in the graph.
+	Rules []*manifest.Rule
+	// Records maps a recorded metric name to the rules that record it.
+	Records map[string][]*manifest.Rule
+
+	deps map[*manifest.Rule][]string
+	keys map[ruleKey]*manifest.Rule
+}
+
+// ruleKey identifies a rule in the graph.
+type ruleKey struct {
+	file       string
+	groupIndex int
+	ruleIndex  int
+}
+
+// New builds the dependency graph of the given rules.
+// the rules whose expression can't be parsed have no dependencies.
+func New(rules []*manifest.Rule) *Graph {
+	g := &Graph{
+		Rules:   rules,
+		Records: map[string][]*manifest.Rule{},
+		deps:    map[*manifest.Rule][]string{},
+		keys:    map[ruleKey]*manifest.Rule{},
+	}
+
+	for _, r := range rules {
+		g.keys[ruleKey{r.File, r.GroupIndex, r.Index}] = r
+		if r.Record != "" {
+			g.Records[r.Record] = append(g.Records[r.Record], r)
+		}
+
+		if expr, err := parser.ParseExpr(r.Expr.StrVal); err == nil {
+			g.deps[r] = MetricNames(expr)
+		}
+	}
+
+	return g
+}
+
+// RuleOf returns the rule that the given context describes.
+func (g *Graph) RuleOf(ctx *linter.ExprContext) (*manifest.Rule, bool) {
+	r, ok := g.keys[ruleKey{ctx.File, ctx.GroupIndex, ctx.RuleIndex}]
+	return r, ok
+}
+
+// Dependencies returns the sorted metric names that the rule reads.
+func (g *Graph) Dependencies(r *manifest.Rule) []string {
+	return g.deps[r]
+}
+
+// Dependents returns the rules that read the given metric.
+func (g *Graph) Dependents(name string) []*manifest.Rule {
+	rules := []*manifest.Rule{}
+	for _, r := range g.Rules {
+		for _, dep := range g.deps[r] {
+			if dep == name {
+				rules = append(rules, r)
+				break
+			}
+		}
+	}
+
+	return rules
+}
+
+// Cycle returns the shortest cycle of the recording rules that starts from the given rule.
+// the first and the last element of the result are the given rule.
+// it returns nil if the rule isn't in any cycle.
+func (g *Graph) Cycle(r *manifest.Rule) []*manifest.Rule {
+	if r.Record == "" {
+		return nil
+	}
+
+	// Breadth-First-Search
+	parents := map[*manifest.Rule]*manifest.Rule{}
+	queue := []*manifest.Rule{r}
+	for len(queue) != 0 {
+		current := queue[0]
+		queue = queue[1:]
+
+		for _, dep := range g.deps[current] {
+			for _, next := range g.Records[dep] {
+				if next == r {
+					cycle := []*manifest.Rule{r}
+					for c := current; c != r; c = parents[c] {
+						cycle = append(cycle, c)
+					}
+					cycle = append(cycle, r)
+
+					// the cycle is constructed in reverse order.
+					for i, j := 0, len(cycle)-1; i < j; i, j = i+1, j-1 {
+						cycle[i], cycle[j] = cycle[j], cycle[i]
+					}
+					return cycle
+				}
+
+				if _, visited := parents[next]; visited {
+					continue
+				}
+				parents[next] = current
+				queue = append(queue, next)
+			}
+		}
+	}
+
+	return nil
+}
+
+// MetricNames returns the sorted metric names that the expression reads.
+// the selectors without any metric name equality matcher are ignored.
+func MetricNames(expr parser.Expr) []string {
+	names := map[string]struct{}{}
+	parser.Inspect(expr, func(n parser.Node, path []parser.Node) error {
+		if vs, ok := n.(*parser.VectorSelector); ok {
+			if name := SelectorMetricName(vs); name != "" {
+				names[name] = struct{}{}
+			}
+		}
+
+		return nil
+	})
+
+	sorted := make([]string, 0, len(names))
+	for name := range names {
+		sorted = append(sorted, name)
+	}
+	sort.Strings(sorted)
+
+	return sorted
+}
+
+// SelectorMetricName returns the metric name that the selector reads.
+// it returns an empty string if the selector has no metric name equality matcher.
+func SelectorMetricName(vs *parser.VectorSelector) string {
+	for _, lm := range vs.LabelMatchers {
+		if lm.Name == labels.MetricName && lm.Type == labels.MatchEqual {
+			return lm.Value
+		}
+	}
+
+	return ""
+}
+
+// IsRecordingRuleName determines whether the metric name follows
+// the `level:metric:operations` naming convention of the recording rules.
+func IsRecordingRuleName(name string) bool {
+	return strings.Contains(name, ":")
+}
@@ -0,0 +1,77 @@
+/*
+MIT License
+
+# Copyright (c) 2022 Drumato
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package rulegraph_test
+
+import (
+	"testing"
+
+	"github.com/Drumato/promqlinter/pkg/manifest"
+	"github.com/Drumato/promqlinter/pkg/rulegraph"
+	"github.com/prometheus/prometheus/promql/parser"
+	"github.com/stretchr/testify/assert"
+)
+
+const testManifest = `spec:
+  groups:
+  - name: example
+    rules:
+    - record: job:a:sum
+      expr: sum by (job) (a) + job:b:sum
+    - record: job:b:sum
+      expr: sum by (job) (b) * job:a:sum
+    - record: job:c:sum
+      expr: sum by (job) (c)
+    - alert: Down
+      expr: job:c:sum == 0 and on (job) job:a:sum
+`
+
+func TestGraph(t *testing.T) {
+	f, err := manifest.Parse("rules.yaml", []byte(testManifest))
+	assert.NoError(t, err)
+	g := rulegraph.New(f.Rules)
+
+	a, b, c, alert := f.Rules[0], f.Rules[1], f.Rules[2], f.Rules[3]
+	assert.Equal(t, []*manifest.Rule{c}, g.Records["job:c:sum"])
+	assert.Equal(t, []string{"job:a:sum", "job:c:sum"}, g.Dependencies(alert))
+	assert.Equal(t, []*manifest.Rule{b, alert}, g.Dependents("job:a:sum"))
+
+	assert.Equal(t, []*manifest.Rule{a, b, a}, g.Cycle(a))
+	assert.Equal(t, []*manifest.Rule{b, a, b}, g.Cycle(b))
+	assert.Nil(t, g.Cycle(c))
+	assert.Nil(t, g.Cycle(alert))
+
+	r, ok := g.RuleOf(alert.Context())
+	assert.True(t, ok)
+	assert.Equal(t, alert, r)
+}
+
+func TestMetricNames(t *testing.T) {
+	expr, err := parser.ParseExpr(`rate(b_total[5m]) / on (job) a or {__name__=~"c.*"} or a offset 1h`)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"a", "b_total"}, rulegraph.MetricNames(expr))
+
+	assert.True(t, rulegraph.IsRecordingRuleName("job:http_requests:rate5m"))
+	assert.False(t, rulegraph.IsRecordingRuleName("http_requests_total"))
+}